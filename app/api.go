@@ -0,0 +1,359 @@
+package app
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pestanko/isstat/core"
+	log "github.com/sirupsen/logrus"
+)
+
+// LatestSnapshot - the alias of the latest snapshot in the API paths
+const LatestSnapshot = "latest"
+
+// apiTokenCookie - cookie keeping the token of the dashboard users
+const apiTokenCookie = "isstat_token"
+
+// apiContentTypes - content types of the snapshot formats served by the API
+var apiContentTypes = map[string]string{
+	"xml":  "application/xml; charset=utf-8",
+	"json": "application/json; charset=utf-8",
+	"csv":  "text/csv; charset=utf-8",
+}
+
+// APIServer - read-only HTTP API over the results
+//
+//	GET /api/notepads                          - notepads with the number of snapshots
+//	GET /api/notepads/{notepad}                - snapshots of the notepad and their formats
+//	GET /api/notepads/{notepad}/{timestamp}.{format}
+//	                                           - raw XML, parsed JSON or CSV of the snapshot,
+//	                                             the timestamp may be "latest"
+//	GET /api/latest                            - the latest snapshot per notepad and extension
+//	GET /api/stats?match=PATTERN               - summaries of the parsed notepads
+//
+// The web dashboard (see registerDashboard) is served by the same handler.
+//
+// When the token is set, the requests have to provide it as "Authorization: Bearer TOKEN".
+// The browsers may open any page with the "?token=TOKEN" query once, the token is then kept in the secure cookie
+// and the browser is redirected to the page without the token in the URL.
+type APIServer struct {
+	App   *IsStatApp
+	Token string
+}
+
+// NotepadIndex - one notepad in the API listing
+type NotepadIndex struct {
+	Name      string `json:"name"`
+	Snapshots int    `json:"snapshots"`
+	Latest    string `json:"latest"`
+}
+
+// SnapshotIndex - one snapshot of the notepad in the API listing
+type SnapshotIndex struct {
+	TimeStamp string   `json:"timestamp"`
+	Formats   []string `json:"formats"`
+}
+
+// LatestIndex - the latest result of the notepad in the API listing
+type LatestIndex struct {
+	TimeStamp string `json:"timestamp"`
+	Ext       string `json:"ext"`
+	Path      string `json:"path"`
+}
+
+// NewAPIServer - creates the API over the application's results, empty token disables the authentication
+func NewAPIServer(app *IsStatApp, token string) *APIServer {
+	return &APIServer{App: app, Token: token}
+}
+
+// Handler - the HTTP handler serving the API
+func (server *APIServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/notepads", server.handleNotepads)
+	mux.HandleFunc("/api/notepads/", server.handleNotepad)
+	mux.HandleFunc("/api/latest", server.handleLatest)
+	mux.HandleFunc("/api/stats", server.handleStats)
+	server.registerDashboard(mux)
+	return server.authenticate(mux)
+}
+
+func (server *APIServer) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entry := log.WithField("method", r.Method).WithField("path", r.URL.Path).WithField("remote", r.RemoteAddr)
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeAPIError(w, http.StatusMethodNotAllowed, "the API is read-only")
+			return
+		}
+
+		if server.Token != "" {
+			exchange := r.Header.Get("Authorization") == "" && r.URL.Query().Get("token") != ""
+			if exchange && server.exchangeToken(w, r) {
+				entry.Debug("API token exchanged for the cookie")
+				return
+			}
+
+			if exchange || !server.authorized(r) {
+				entry.Warning("Unauthorized API request")
+				w.Header().Set("WWW-Authenticate", `Bearer realm="isstat"`)
+				writeAPIError(w, http.StatusUnauthorized, "invalid or missing token")
+				return
+			}
+		}
+
+		entry.Debug("API request")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authorized - whether the request provides the token in the header or the cookie
+func (server *APIServer) authorized(r *http.Request) bool {
+	if header := r.Header.Get("Authorization"); header != "" {
+		return strings.HasPrefix(header, "Bearer ") && server.validToken(strings.TrimPrefix(header, "Bearer "))
+	}
+
+	cookie, err := r.Cookie(apiTokenCookie)
+	return err == nil && server.validToken(cookie.Value)
+}
+
+// exchangeToken - stores the valid token from the query in the cookie and redirects to the URL without it,
+// so the token does not stay in the browser history and the proxy logs
+func (server *APIServer) exchangeToken(w http.ResponseWriter, r *http.Request) bool {
+	query := r.URL.Query()
+	if !server.validToken(query.Get("token")) {
+		return false
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     apiTokenCookie,
+		Value:    server.Token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   isSecureRequest(r),
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	query.Del("token")
+	target := *r.URL
+	target.RawQuery = query.Encode()
+	http.Redirect(w, r, target.RequestURI(), http.StatusSeeOther)
+	return true
+}
+
+// isSecureRequest - the request came over the HTTPS, directly or through the TLS terminating proxy
+func isSecureRequest(r *http.Request) bool {
+	return r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
+func (server *APIServer) validToken(token string) bool {
+	return subtle.ConstantTimeCompare([]byte(token), []byte(server.Token)) == 1
+}
+
+func (server *APIServer) handleNotepads(w http.ResponseWriter, r *http.Request) {
+	snapshots, err := server.snapshots()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	notepads := []NotepadIndex{}
+	for name, items := range snapshots {
+		notepads = append(notepads, NotepadIndex{
+			Name:      name,
+			Snapshots: len(items),
+			Latest:    items[len(items)-1].TimeStamp,
+		})
+	}
+
+	sort.Slice(notepads, func(i, j int) bool {
+		return notepads[i].Name < notepads[j].Name
+	})
+	writeAPIJSON(w, notepads)
+}
+
+func (server *APIServer) handleNotepad(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/notepads/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		server.handleSnapshots(w, parts[0])
+	case len(parts) == 2 && parts[0] != "":
+		server.handleSnapshot(w, parts[0], parts[1])
+	default:
+		writeAPIError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (server *APIServer) handleSnapshots(w http.ResponseWriter, notepad string) {
+	snapshots, err := server.snapshots()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	items, ok := snapshots[notepad]
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("notepad '%s' not found", notepad))
+		return
+	}
+	writeAPIJSON(w, items)
+}
+
+func (server *APIServer) handleSnapshot(w http.ResponseWriter, notepad, snapshot string) {
+	dot := strings.Index(snapshot, ".")
+	if dot < 0 {
+		writeAPIError(w, http.StatusNotFound, "missing format, expected {timestamp}.{xml|json|csv}")
+		return
+	}
+
+	timestamp, format := snapshot[:dot], snapshot[dot+1:]
+	contentType, ok := apiContentTypes[format]
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("unknown format '%s'", format))
+		return
+	}
+
+	content, err := server.snapshotContent(notepad, timestamp, format)
+	if err != nil {
+		log.WithError(err).WithField("notepad", notepad).WithField("snapshot", snapshot).Error("Unable to get the snapshot")
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if content == nil {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("snapshot '%s' of the notepad '%s' not found", snapshot, notepad))
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(content)
+}
+
+// snapshotContent - content of the snapshot in the format, nil if the snapshot does not exist
+//
+// The CSV is converted from the parsed JSON when it was not stored.
+func (server *APIServer) snapshotContent(notepad, timestamp, format string) ([]byte, error) {
+	item, ok, err := server.findSnapshot(notepad, timestamp, format)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return server.App.Results.GetContent(&item)
+	}
+
+	if format != "csv" {
+		return nil, nil
+	}
+
+	item, ok, err = server.findSnapshot(notepad, timestamp, "json")
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	statistics, err := server.App.convertStudentInfo(&item)
+	if err != nil {
+		return nil, err
+	}
+	return server.App.marshalStatistics(notepad, statistics)
+}
+
+// findSnapshot - the stored result of the notepad's snapshot with the base extension
+func (server *APIServer) findSnapshot(notepad, timestamp, ext string) (core.ResultItem, bool, error) {
+	items, err := server.App.Results.List()
+	if err != nil {
+		return core.ResultItem{}, false, err
+	}
+
+	var found core.ResultItem
+	for _, item := range items {
+		if item.Name != notepad || item.TimeStamp == "" || item.BaseExt() != ext {
+			continue
+		}
+		if timestamp == LatestSnapshot && item.TimeStamp > found.TimeStamp || item.TimeStamp == timestamp {
+			found = item
+		}
+	}
+	return found, found.Name != "", nil
+}
+
+func (server *APIServer) handleLatest(w http.ResponseWriter, r *http.Request) {
+	latest := make(map[string]map[string]LatestIndex)
+	for name, extensions := range server.App.GetLatest() {
+		latest[name] = make(map[string]LatestIndex)
+		for ext, item := range extensions {
+			latest[name][ext] = LatestIndex{
+				TimeStamp: item.TimeStamp,
+				Ext:       item.Ext,
+				Path:      fmt.Sprintf("/api/notepads/%s/%s.%s", item.Name, item.TimeStamp, item.BaseExt()),
+			}
+		}
+	}
+	writeAPIJSON(w, latest)
+}
+
+func (server *APIServer) handleStats(w http.ResponseWriter, r *http.Request) {
+	patterns := r.URL.Query()["match"]
+	if len(patterns) == 0 {
+		patterns = []string{"*.json*"}
+	}
+
+	summaries, err := server.App.Stats(patterns)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if summaries == nil {
+		summaries = []core.NotepadSummary{}
+	}
+	writeAPIJSON(w, summaries)
+}
+
+// snapshots - the timestamped snapshots grouped by the notepad, ordered from the oldest
+func (server *APIServer) snapshots() (map[string][]SnapshotIndex, error) {
+	items, err := server.App.Results.List()
+	if err != nil {
+		return nil, err
+	}
+
+	formats := make(map[string]map[string][]string)
+	for _, item := range items {
+		if item.TimeStamp == "" {
+			continue
+		}
+		if _, ok := apiContentTypes[item.BaseExt()]; !ok {
+			continue
+		}
+		if formats[item.Name] == nil {
+			formats[item.Name] = make(map[string][]string)
+		}
+		formats[item.Name][item.TimeStamp] = append(formats[item.Name][item.TimeStamp], item.BaseExt())
+	}
+
+	snapshots := make(map[string][]SnapshotIndex)
+	for name, timestamps := range formats {
+		for timestamp, exts := range timestamps {
+			sort.Strings(exts)
+			snapshots[name] = append(snapshots[name], SnapshotIndex{TimeStamp: timestamp, Formats: exts})
+		}
+		sort.Slice(snapshots[name], func(i, j int) bool {
+			return snapshots[name][i].TimeStamp < snapshots[name][j].TimeStamp
+		})
+	}
+	return snapshots, nil
+}
+
+func writeAPIJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", apiContentTypes["json"])
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.WithError(err).Error("Unable to write the API response")
+	}
+}
+
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", apiContentTypes["json"])
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,188 @@
+package app
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pestanko/isstat/core"
+)
+
+const testAPIToken = "secret-token"
+
+func newTestAPI(t *testing.T) (*httptest.Server, func()) {
+	application, cleanup := newResultsTestApp(t,
+		core.ResultItem{Name: "hw01", TimeStamp: "2020-03-01T10-00-00", Ext: "xml", Data: []byte("<BLOKY_OBSAH/>")},
+		core.ResultItem{Name: "hw01", TimeStamp: "2020-03-01T10-00-00", Ext: "json", Data: testStudents(`{"index":0,"points":5,"bonus":1,"final":true}`)},
+		core.ResultItem{Name: "hw01", TimeStamp: "2020-03-02T10-00-00", Ext: "xml", Data: []byte("<BLOKY_OBSAH></BLOKY_OBSAH>")},
+		core.ResultItem{Name: "hw02", TimeStamp: "2020-03-01T10-00-00", Ext: "xml", Data: []byte("<BLOKY_OBSAH/>")},
+	)
+
+	server := httptest.NewServer(NewAPIServer(application, testAPIToken).Handler())
+	return server, func() {
+		server.Close()
+		cleanup()
+	}
+}
+
+func apiGet(t *testing.T, server *httptest.Server, path, token string) (*http.Response, string) {
+	request, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+	if err != nil {
+		t.Fatalf("FAIL: Unable to create the request: %v", err)
+	}
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	response, err := server.Client().Do(request)
+	if err != nil {
+		t.Fatalf("FAIL: Request %s failed: %v", path, err)
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatalf("FAIL: Unable to read the response: %v", err)
+	}
+	return response, string(body)
+}
+
+func TestAPI_RequiresToken(t *testing.T) {
+	// GIVEN
+	server, cleanup := newTestAPI(t)
+	defer cleanup()
+
+	// WHEN
+	missing, _ := apiGet(t, server, "/api/notepads", "")
+	invalid, _ := apiGet(t, server, "/api/notepads", "wrong")
+	valid, _ := apiGet(t, server, "/api/notepads", testAPIToken)
+
+	request, _ := http.NewRequest(http.MethodGet, server.URL+"/api/notepads", nil)
+	request.Header.Set("Authorization", testAPIToken)
+	bare, err := server.Client().Do(request)
+	if err != nil {
+		t.Fatalf("FAIL: Request failed: %v", err)
+	}
+	_ = bare.Body.Close()
+
+	// THEN
+	if missing.StatusCode != http.StatusUnauthorized || invalid.StatusCode != http.StatusUnauthorized {
+		t.Errorf("FAIL: Requests without the valid token should be unauthorized: %d, %d", missing.StatusCode, invalid.StatusCode)
+	}
+	if bare.StatusCode != http.StatusUnauthorized {
+		t.Errorf("FAIL: Token without the Bearer scheme should be unauthorized: %d", bare.StatusCode)
+	}
+	if valid.StatusCode != http.StatusOK {
+		t.Errorf("FAIL: Request with the token should succeed: %d", valid.StatusCode)
+	}
+}
+
+func TestAPI_ReadOnly(t *testing.T) {
+	// GIVEN
+	server, cleanup := newTestAPI(t)
+	defer cleanup()
+
+	// WHEN
+	request, _ := http.NewRequest(http.MethodDelete, server.URL+"/api/notepads/hw01", nil)
+	request.Header.Set("Authorization", "Bearer "+testAPIToken)
+	response, err := server.Client().Do(request)
+	if err != nil {
+		t.Fatalf("FAIL: Request failed: %v", err)
+	}
+	_ = response.Body.Close()
+
+	// THEN
+	if response.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("FAIL: Expected %d, got %d", http.StatusMethodNotAllowed, response.StatusCode)
+	}
+}
+
+func TestAPI_ListsNotepadsAndSnapshots(t *testing.T) {
+	// GIVEN
+	server, cleanup := newTestAPI(t)
+	defer cleanup()
+
+	// WHEN
+	_, body := apiGet(t, server, "/api/notepads", testAPIToken)
+	var notepads []NotepadIndex
+	if err := json.Unmarshal([]byte(body), &notepads); err != nil {
+		t.Fatalf("FAIL: Unable to decode the notepads: %v", err)
+	}
+
+	_, body = apiGet(t, server, "/api/notepads/hw01", testAPIToken)
+	var snapshots []SnapshotIndex
+	if err := json.Unmarshal([]byte(body), &snapshots); err != nil {
+		t.Fatalf("FAIL: Unable to decode the snapshots: %v", err)
+	}
+
+	// THEN
+	if len(notepads) != 2 || notepads[0].Name != "hw01" || notepads[0].Snapshots != 2 || notepads[0].Latest != "2020-03-02T10-00-00" {
+		t.Errorf("FAIL: Unexpected notepads %+v", notepads)
+	}
+	if len(snapshots) != 2 || strings.Join(snapshots[0].Formats, ",") != "json,xml" || snapshots[1].TimeStamp != "2020-03-02T10-00-00" {
+		t.Errorf("FAIL: Unexpected snapshots %+v", snapshots)
+	}
+
+	if response, _ := apiGet(t, server, "/api/notepads/missing", testAPIToken); response.StatusCode != http.StatusNotFound {
+		t.Errorf("FAIL: Missing notepad should not be found: %d", response.StatusCode)
+	}
+}
+
+func TestAPI_GetsSnapshotContent(t *testing.T) {
+	// GIVEN
+	server, cleanup := newTestAPI(t)
+	defer cleanup()
+
+	cases := []struct {
+		path     string
+		status   int
+		contains string
+	}{
+		{"/api/notepads/hw01/2020-03-01T10-00-00.xml", http.StatusOK, "<BLOKY_OBSAH/>"},
+		{"/api/notepads/hw01/latest.xml", http.StatusOK, "<BLOKY_OBSAH></BLOKY_OBSAH>"},
+		{"/api/notepads/hw01/latest.json", http.StatusOK, testStudentID},
+		{"/api/notepads/hw01/2020-03-01T10-00-00.csv", http.StatusOK, testStudentID},
+		{"/api/notepads/hw02/latest.json", http.StatusNotFound, "not found"},
+		{"/api/notepads/hw01/latest.pdf", http.StatusNotFound, "unknown format"},
+	}
+
+	for _, c := range cases {
+		// WHEN
+		response, body := apiGet(t, server, c.path, testAPIToken)
+
+		// THEN
+		if response.StatusCode != c.status || !strings.Contains(body, c.contains) {
+			t.Errorf("FAIL: %s returned %d '%s', expected %d containing '%s'", c.path, response.StatusCode, body, c.status, c.contains)
+		}
+	}
+}
+
+func TestAPI_LatestAndStats(t *testing.T) {
+	// GIVEN
+	server, cleanup := newTestAPI(t)
+	defer cleanup()
+
+	// WHEN
+	_, body := apiGet(t, server, "/api/latest", testAPIToken)
+	var latest map[string]map[string]LatestIndex
+	if err := json.Unmarshal([]byte(body), &latest); err != nil {
+		t.Fatalf("FAIL: Unable to decode the latest: %v", err)
+	}
+
+	_, body = apiGet(t, server, "/api/stats?match=hw01.*.json", testAPIToken)
+	var summaries []core.NotepadSummary
+	if err := json.Unmarshal([]byte(body), &summaries); err != nil {
+		t.Fatalf("FAIL: Unable to decode the stats: %v", err)
+	}
+
+	// THEN
+	if latest["hw01"]["xml"].Path != "/api/notepads/hw01/2020-03-02T10-00-00.xml" {
+		t.Errorf("FAIL: Unexpected latest %+v", latest)
+	}
+	if len(summaries) != 1 || summaries[0].Name != "hw01" || summaries[0].Students != 1 {
+		t.Errorf("FAIL: Unexpected stats %+v", summaries)
+	}
+}
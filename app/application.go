@@ -2,19 +2,25 @@ package app
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/pestanko/isstat/core"
 	"github.com/pestanko/isstat/parsers"
 	log "github.com/sirupsen/logrus"
 	"os"
+	"path"
+	"path/filepath"
 	"sort"
+	"sync"
+	"time"
 )
 
 // IsStatApp - Is MUNI Statistics application
 type IsStatApp struct {
-	Client  core.CourseClient
-	Parser  parsers.Parser
-	Results core.Results
-	Config  *Config
+	Client   core.CourseClient
+	Parser   parsers.Parser
+	Results  core.Results
+	Register *core.StudentsRegister
+	Config   *Config
 }
 
 // Fetch - fetches the notepads content
@@ -23,21 +29,112 @@ func (app *IsStatApp) Fetch(notepads []string) ([]core.ResultItem, error) {
 	return app.FetchWithTimestamp(notepads, timestamp)
 }
 
+// DiscoverNotepads - lists the course notepads whose shortnames match any of the glob patterns
+//
+// No patterns means all the notepads.
+func (app *IsStatApp) DiscoverNotepads(patterns []string) ([]core.NotepadInfo, error) {
+	notepads, err := app.Client.ListNotepads()
+	if err != nil {
+		log.WithError(err).Error("Unable to list the course notepads")
+		return nil, err
+	}
+
+	if len(patterns) == 0 {
+		return notepads, nil
+	}
+
+	var matched []core.NotepadInfo
+	for _, notepad := range notepads {
+		ok, err := matchAny(patterns, notepad.Shortname)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, notepad)
+		}
+	}
+
+	log.WithField("patterns", patterns).WithField("matched", len(matched)).Info("Discovered notepads")
+	return matched, nil
+}
+
+func matchAny(patterns []string, name string) (bool, error) {
+	for _, pattern := range patterns {
+		ok, err := path.Match(pattern, name)
+		if err != nil {
+			return false, fmt.Errorf("invalid pattern '%s': %v", pattern, err)
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // FetchWithTimestamp - fetches the notepads content
+//
+// Notepads are fetched in parallel by the configured number of workers,
+// the returned items keep the order of the provided notepads.
+// The first error (in the notepads order) is returned together with the successfully fetched items.
 func (app *IsStatApp) FetchWithTimestamp(notepads []string, timestamp string) ([]core.ResultItem, error) {
+	fetched, errs := app.FetchEach(notepads, timestamp)
+
 	var items []core.ResultItem
+	var firstErr error
 
-	for _, notepad := range notepads {
-		resultItem, err2 := app.FetchOne(notepad, timestamp)
-		if err2 != nil {
-			return items, err2
+	for i, item := range fetched {
+		if errs[i] != nil {
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+			continue
 		}
-		if resultItem.Name == "" {
+		if item.Name == "" {
 			continue
 		}
-		items = append(items, resultItem)
+		items = append(items, item)
 	}
-	return items, nil
+	return items, firstErr
+}
+
+// FetchEach - fetches the notepads content in parallel, the item and the error are returned for each notepad
+func (app *IsStatApp) FetchEach(notepads []string, timestamp string) ([]core.ResultItem, []error) {
+	items := make([]core.ResultItem, len(notepads))
+	errs := make([]error, len(notepads))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	workers := app.fetchWorkers(len(notepads))
+	log.WithField("workers", workers).WithField("notepads", len(notepads)).Debug("Starting fetch workers")
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				items[i], errs[i] = app.FetchOne(notepads[i], timestamp)
+			}
+		}()
+	}
+
+	for i := range notepads {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return items, errs
+}
+
+func (app *IsStatApp) fetchWorkers(jobs int) int {
+	workers := 1
+	if app.Config != nil && app.Config.Workers > 0 {
+		workers = app.Config.Workers
+	}
+	if workers > jobs {
+		workers = jobs
+	}
+	return workers
 }
 
 func (app *IsStatApp) FetchOne(notepad string, timestamp string) (core.ResultItem, error) {
@@ -59,6 +156,53 @@ func (app *IsStatApp) FetchOne(notepad string, timestamp string) (core.ResultIte
 	return resultItem, nil
 }
 
+// Push - writes the contents to the students' notepads, only the changed contents are written
+//
+// The current notepad content is read even in the dry run mode, so the planned changes are exact,
+// but nothing is written.
+func (app *IsStatApp) Push(notepad string, contents []core.StudentContent) ([]core.PushChange, error) {
+	log.WithField("name", notepad).WithField("students", len(contents)).Info("Pushing notepad content")
+
+	reader := app.Client
+	reader.DryRun = false
+	current, err := reader.GetNotepadContent(notepad)
+	if err != nil {
+		log.WithField("name", notepad).WithError(err).Error("Unable to fetch the current content")
+		return nil, err
+	}
+
+	changes := core.PlanPush(&current, contents)
+	if app.Client.DryRun {
+		return changes, nil
+	}
+
+	var toWrite []core.StudentContent
+	indexes := make(map[string]int)
+	for i, change := range changes {
+		if !change.Changed() {
+			continue
+		}
+		indexes[change.Uco] = i
+		toWrite = append(toWrite, core.StudentContent{Uco: change.Uco, Content: change.New})
+	}
+
+	failed := 0
+	for _, result := range app.Client.WriteNotepadContent(notepad, toWrite) {
+		change := &changes[indexes[result.Uco]]
+		if result.Err != nil {
+			change.Error = result.Err.Error()
+			failed++
+			continue
+		}
+		change.Written = true
+	}
+
+	if failed > 0 {
+		return changes, fmt.Errorf("unable to write %d of %d students' contents", failed, len(toWrite))
+	}
+	return changes, nil
+}
+
 func (app *IsStatApp) ConvertToCSV(patterns []string) ([]core.ResultItem, error) {
 	var items []core.ResultItem
 
@@ -84,7 +228,7 @@ func (app *IsStatApp) ConvertToCSVOne(notepad string) (core.ResultItem, error) {
 
 	jsonItem := core.NewResultItemFromFullName(notepad)
 
-	if jsonItem.Ext != "json" {
+	if jsonItem.BaseExt() != "json" {
 		return core.ResultItem{}, nil
 	}
 
@@ -95,30 +239,99 @@ func (app *IsStatApp) ConvertToCSVOne(notepad string) (core.ResultItem, error) {
 
 	csvItem := core.NewResultItem(jsonItem.Name, jsonItem.TimeStamp, "csv")
 
-	if err := core.WriteStatisticsToCSVFile(app.Results.GetPath(&csvItem), csvContent); err != nil {
+	csvItem.Data, err = app.marshalStatistics(jsonItem.Name, csvContent)
+	if err != nil {
+		return csvItem, err
+	}
+
+	if err := app.Results.Store(&csvItem); err != nil {
 		return csvItem, err
 	}
 
 	return csvItem, err
 }
 
+// Parse - parses the fetched notepads matching the patterns
+//
+// The students register is loaded before and saved after parsing (see WithStudentsRegister).
 func (app *IsStatApp) Parse(patterns []string) (map[string][]core.StudentInfo, error) {
 	var items = make(map[string][]core.StudentInfo)
 	log.WithField("patterns", patterns).Info("Parse notepads")
 
-	fileNames := app.Results.GlobAll(patterns)
-	log.WithField("filenames", fileNames).Info("found filenames")
+	err := app.WithStudentsRegister(func() error {
+		fileNames := app.Results.GlobAll(patterns)
+		log.WithField("filenames", fileNames).Info("found filenames")
 
-	for _, notepad := range fileNames {
-		info, err := app.ParseOne(notepad)
-		if err != nil {
-			log.WithError(err).WithField("notepad", notepad).Error("Error in parsing the notepad")
-			continue
+		for _, notepad := range fileNames {
+			info, err := app.ParseOne(notepad)
+			if err != nil {
+				log.WithError(err).WithField("notepad", notepad).Error("Error in parsing the notepad")
+				continue
+			}
+
+			items[notepad] = info
 		}
+		return nil
+	})
+	return items, err
+}
+
+// WithStudentsRegister - runs the action with the students register loaded from the configured file
+//
+// The register file is locked for the whole action, so the concurrent runs
+// can not register different pseudonyms for the same student.
+// The register is saved after the action even if the action fails.
+func (app *IsStatApp) WithStudentsRegister(action func() error) error {
+	return app.withStudentsRegister(action, true)
+}
 
-		items[notepad] = info
+// withStudentsRegister - see WithStudentsRegister, the register is not saved after the failed action unless saveOnError
+func (app *IsStatApp) withStudentsRegister(action func() error, saveOnError bool) error {
+	if app.Config == nil || app.Config.Register == "" {
+		return action()
 	}
-	return items, nil
+
+	file := app.Config.Register
+	entry := log.WithField("register", file)
+
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		entry.WithError(err).Error("Unable to create the students register directory")
+		return err
+	}
+
+	lock, err := core.LockFile(file + ".lock")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := lock.Unlock(); err != nil {
+			entry.WithError(err).Warning("Unable to release the students register lock")
+		}
+	}()
+
+	if err := app.Register.Import(file); err != nil && !os.IsNotExist(err) {
+		entry.WithError(err).Error("Unable to load the students register")
+		return err
+	}
+	entry.WithField("students", len(app.Register.Users)).Info("Students register loaded")
+
+	actionErr := action()
+
+	if app.Config.DryRun {
+		return actionErr
+	}
+
+	if actionErr != nil && !saveOnError {
+		entry.WithError(actionErr).Warning("Students register not saved after the failed action")
+		return actionErr
+	}
+
+	if err := app.Register.Export(file); err != nil {
+		return err
+	}
+	entry.WithField("students", len(app.Register.Users)).Info("Students register saved")
+
+	return actionErr
 }
 
 func (app *IsStatApp) ParseOne(notepad string) ([]core.StudentInfo, error) {
@@ -126,7 +339,7 @@ func (app *IsStatApp) ParseOne(notepad string) ([]core.StudentInfo, error) {
 
 	resultItem := core.NewResultItemFromFullName(notepad)
 
-	if resultItem.Ext != "xml" {
+	if resultItem.BaseExt() != "xml" {
 		return []core.StudentInfo{}, nil
 	}
 
@@ -137,21 +350,41 @@ func (app *IsStatApp) ParseOne(notepad string) ([]core.StudentInfo, error) {
 
 	jsonitem := core.NewResultItem(resultItem.Name, resultItem.TimeStamp, "json")
 
-	data, err := json.Marshal(info)
-	if err != nil {
-		log.WithError(err).WithField("notepad", notepad).Error("Unable to marshall json with data")
-		return info, err
+	// the new pseudonyms are not saved in the dry run (see WithStudentsRegister), so neither are the results using them
+	if app.Config != nil && app.Config.DryRun {
+		log.WithField("notepad", notepad).Info("Dry run, the parsed notepad is not stored")
+		return info, nil
 	}
 
-	jsonitem.Data = data
-
-	if err := app.Results.Store(&jsonitem); err != nil {
+	if err := app.storeStudentInfo(&jsonitem, info); err != nil {
 		log.WithError(err).WithField("notepad", notepad).WithField("timestamp", jsonitem.TimeStamp).Error("Unable to store result")
 		return info, err
 	}
 	return info, nil
 }
 
+// storeStudentInfo - stores the parsed students as the JSON result item
+//
+// The item without the timestamp is stored as is, no new timestamp is assigned.
+func (app *IsStatApp) storeStudentInfo(item *core.ResultItem, info []core.StudentInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		log.WithError(err).WithField("name", item.Name).Error("Unable to marshall json with data")
+		return err
+	}
+
+	item.Data = data
+	return app.storeResultItem(item)
+}
+
+// storeResultItem - stores the item, the item without the timestamp is kept without it
+func (app *IsStatApp) storeResultItem(item *core.ResultItem) error {
+	if item.TimeStamp == "" {
+		return app.Results.StoreWithoutTimestamp(item)
+	}
+	return app.Results.Store(item)
+}
+
 func (app *IsStatApp) parseResultItem(item *core.ResultItem) ([]core.StudentInfo, error) {
 	fileContent, err := app.Results.GetContent(item)
 	if err != nil {
@@ -167,17 +400,126 @@ func (app *IsStatApp) parseResultItem(item *core.ResultItem) ([]core.StudentInfo
 }
 
 func (app *IsStatApp) convertStudentInfo(item *core.ResultItem) ([]core.CSVStatistic, error) {
-	fileContent, err := app.Results.GetContent(item)
+	infoContent, err := app.readStudentInfo(item)
 	if err != nil {
 		return []core.CSVStatistic{}, err
 	}
 
-	infoContent, err := core.UnmarshalStudentInfo(fileContent)
+	return core.ConvertSubmissionsToCSVStatistics(infoContent), nil
+}
+
+// marshalStatistics - marshals the notepad's statistics to the CSV of the configured format
+func (app *IsStatApp) marshalStatistics(notepad string, statistics []core.CSVStatistic) ([]byte, error) {
+	if app.Config == nil {
+		return core.MarshalStatisticsToCSV(statistics)
+	}
+	format := app.Config.CSVFormat(notepad)
+	return format.Marshal(statistics)
+}
+
+func (app *IsStatApp) readStudentInfo(item *core.ResultItem) ([]core.StudentInfo, error) {
+	fileContent, err := app.Results.GetContent(item)
 	if err != nil {
-		return []core.CSVStatistic{}, err
+		return []core.StudentInfo{}, err
 	}
 
-	return core.ConvertSubmissionsToCSVStatistics(infoContent), nil
+	return core.UnmarshalStudentInfo(fileContent)
+}
+
+// ParsedSnapshot - the parsed snapshot with its students and their summary
+type ParsedSnapshot struct {
+	Item     core.ResultItem
+	Students []core.StudentInfo
+	Summary  core.NotepadSummary
+}
+
+// Stats - computes the score summaries for the parsed notepads matching the patterns
+func (app *IsStatApp) Stats(patterns []string) ([]core.NotepadSummary, error) {
+	snapshots, err := app.ParsedSnapshots(patterns)
+	summaries := make([]core.NotepadSummary, len(snapshots))
+	for i := range snapshots {
+		summaries[i] = snapshots[i].Summary
+	}
+	return summaries, err
+}
+
+// ParsedSnapshots - reads the parsed (compressed or not) snapshots matching the patterns and computes their summaries
+//
+// The snapshots are ordered by the notepad and the timestamp, the unreadable ones are skipped.
+func (app *IsStatApp) ParsedSnapshots(patterns []string) ([]ParsedSnapshot, error) {
+	var snapshots []ParsedSnapshot
+	log.WithField("patterns", patterns).Info("Notepads statistics")
+
+	fileNames := app.Results.GlobAll(patterns)
+	log.WithField("filenames", fileNames).Info("found filenames")
+
+	for _, notepad := range fileNames {
+		item := core.NewResultItemFromFullName(notepad)
+		if item.BaseExt() != "json" {
+			continue
+		}
+
+		info, err := app.readStudentInfo(&item)
+		if err != nil {
+			log.WithError(err).WithField("notepad", notepad).Error("Unable to read the parsed notepad")
+			continue
+		}
+
+		item.Data = nil
+		snapshots = append(snapshots, ParsedSnapshot{
+			Item:     item,
+			Students: info,
+			Summary:  core.ComputeNotepadSummary(item.Name, item.TimeStamp, info),
+		})
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		if snapshots[i].Item.Name != snapshots[j].Item.Name {
+			return snapshots[i].Item.Name < snapshots[j].Item.Name
+		}
+		return snapshots[i].Item.TimeStamp < snapshots[j].Item.TimeStamp
+	})
+
+	return snapshots, nil
+}
+
+// AttemptsStats - analyses the attempts to success of the parsed notepads matching the patterns
+//
+// The full and pass points are taken from the notepads' config, the students who did not pass
+// in at least giveUpAfter attempts are counted as the ones who gave up.
+func (app *IsStatApp) AttemptsStats(patterns []string, giveUpAfter int) ([]core.AttemptsAnalysis, error) {
+	var analyses []core.AttemptsAnalysis
+	log.WithField("patterns", patterns).Info("Notepads attempts")
+
+	for _, notepad := range app.Results.GlobAll(patterns) {
+		item := core.NewResultItemFromFullName(notepad)
+		if item.BaseExt() != "json" {
+			continue
+		}
+
+		info, err := app.readStudentInfo(&item)
+		if err != nil {
+			log.WithError(err).WithField("notepad", notepad).Error("Unable to read the parsed notepad")
+			continue
+		}
+
+		var notepadConfig NotepadConfig
+		if app.Config != nil {
+			notepadConfig = app.Config.NotepadConfig(item.Name)
+		}
+
+		analyses = append(analyses, core.NewAttemptsAnalysis(item.Name, item.TimeStamp, info,
+			notepadConfig.FullPoints, notepadConfig.PassPoints, giveUpAfter))
+	}
+
+	sort.Slice(analyses, func(i, j int) bool {
+		if analyses[i].Notepad != analyses[j].Notepad {
+			return analyses[i].Notepad < analyses[j].Notepad
+		}
+		return analyses[i].TimeStamp < analyses[j].TimeStamp
+	})
+
+	return analyses, nil
 }
 
 func (app *IsStatApp) CleanResults(patterns []string, limit int) ([]core.ResultItem, error) {
@@ -200,7 +542,7 @@ func (app *IsStatApp) CleanResults(patterns []string, limit int) ([]core.ResultI
 						"fileName": item.GetFullName(),
 					}).Info("Clean: Removing result")
 
-					if err := os.Remove(app.Results.GetPath(&item)); err != nil {
+					if err := app.Results.Delete(&item); err != nil {
 						log.WithField("fullname", item.GetFullName()).WithError(err).Error("Unable to remove")
 						continue
 					}
@@ -309,16 +651,57 @@ func CategorizeByName(items []core.ResultItem) (result map[string][]core.ResultI
 func GetApplication(config *Config) (IsStatApp, error) {
 	client := core.NewCourseClient(config.Muni.URL, config.Muni.Token, config.Muni.Faculty, config.Muni.Course)
 	client.DryRun = config.DryRun
+	client.Timeout = time.Duration(config.Muni.Timeout) * time.Second
+	client.Retries = config.Muni.Retries
+	client.RateLimit = config.Muni.RateLimit
 
 	register := parsers.GetParserRegister()
 	register.Register("default", &parsers.KontrFunctionalityParser{})
 	parser := register.GetOrDefault(config.Parser)
+	studentsRegister, err := newStudentsRegister(&config.Pseudonym)
+	if err != nil {
+		return IsStatApp{}, err
+	}
+
 	basicParser := parsers.BasicParser{
-		StudentsRegister:     core.NewStudentsRegister(),
+		StudentsRegister:     &studentsRegister,
 		NotepadContentParser: parser,
+		KontrAccounts:        config.KontrAccounts,
 	}
 
-	return IsStatApp{Client: client, Parser: &basicParser, Results: core.NewResults(config.Results, config.WithoutTimestamp), Config: config}, nil
+	store, err := core.OpenStore(config.Storage, config.Results)
+	if err != nil {
+		return IsStatApp{}, err
+	}
+
+	return IsStatApp{
+		Client:   client,
+		Parser:   &basicParser,
+		Results:  core.NewResultsWithStore(store, config.WithoutTimestamp),
+		Register: &studentsRegister,
+		Config:   config,
+	}, nil
+}
+
+// Close - releases the application resources (results store)
+func (app *IsStatApp) Close() error {
+	return app.Results.Close()
+}
+
+func newStudentsRegister(config *PseudonymConfig) (core.StudentsRegister, error) {
+	switch config.Mode {
+	case "", PseudonymRandom:
+		return core.NewStudentsRegister(), nil
+	case PseudonymHMAC:
+		key, err := config.LoadKey()
+		if err != nil {
+			log.WithError(err).Error("Unable to load the pseudonym key")
+			return core.StudentsRegister{}, err
+		}
+		return core.NewKeyedStudentsRegister(key)
+	default:
+		return core.StudentsRegister{}, fmt.Errorf("unknown pseudonym mode '%s'", config.Mode)
+	}
 }
 
 func SetupLogger(loggingLevel string) {
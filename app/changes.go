@@ -0,0 +1,82 @@
+package app
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pestanko/isstat/core"
+	log "github.com/sirupsen/logrus"
+)
+
+// NotepadSnapshots - the notepad's results with the extension (compressed or not), ordered from the oldest
+func (app *IsStatApp) NotepadSnapshots(notepad, ext string) []core.ResultItem {
+	var snapshots []core.ResultItem
+	for _, item := range app.PatternsToResultItems([]string{notepad + ".*"}) {
+		if item.Name == notepad && item.BaseExt() == ext {
+			snapshots = append(snapshots, item)
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].TimeStamp < snapshots[j].TimeStamp
+	})
+	return snapshots
+}
+
+// LastSnapshotTime - time of the newest fetched (xml) snapshot of the notepad, zero time if there is none
+func (app *IsStatApp) LastSnapshotTime(notepad string) time.Time {
+	snapshots := app.NotepadSnapshots(notepad, "xml")
+	if len(snapshots) == 0 {
+		return time.Time{}
+	}
+
+	last, err := time.ParseInLocation(core.TimestampFormat, snapshots[len(snapshots)-1].TimeStamp, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return last
+}
+
+// Changes - change events between the consecutive parsed snapshots of the notepad
+//
+// Only the last two snapshots are compared unless all is set,
+// the pairs whose newer snapshot is not after since (if provided) are skipped.
+func (app *IsStatApp) Changes(notepad, since string, all bool) ([]core.ChangeEvent, error) {
+	snapshots := app.NotepadSnapshots(notepad, "json")
+	log.WithField("notepad", notepad).WithField("snapshots", len(snapshots)).Info("Detecting changes")
+
+	if len(snapshots) < 2 {
+		return nil, nil
+	}
+
+	first := 1
+	if !all {
+		first = len(snapshots) - 1
+	}
+
+	var events []core.ChangeEvent
+	previous, err := app.readStudentInfo(&snapshots[first-1])
+	if err != nil {
+		return nil, err
+	}
+
+	for i := first; i < len(snapshots); i++ {
+		current, err := app.readStudentInfo(&snapshots[i])
+		if err != nil {
+			return events, err
+		}
+
+		if since == "" || snapshots[i].TimeStamp > since {
+			events = append(events, app.ChangesBetween(notepad, &snapshots[i-1], &snapshots[i], previous, current)...)
+		}
+		previous = current
+	}
+
+	return events, nil
+}
+
+// ChangesBetween - change events between the two parsed snapshots
+func (app *IsStatApp) ChangesBetween(notepad string, from, to *core.ResultItem, previous, current []core.StudentInfo) []core.ChangeEvent {
+	detector := core.ChangeDetector{Notepad: notepad}
+	return detector.Detect(from.TimeStamp, to.TimeStamp, previous, current)
+}
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/pestanko/isstat/core"
+)
+
+func TestNotepadSnapshots_Compressed(t *testing.T) {
+	// GIVEN
+	compressed := gzipTestData([]byte("[]"))
+	application, cleanup := newResultsTestApp(t,
+		core.ResultItem{Name: "hw01", TimeStamp: "2020-03-02T10-00-00", Ext: "json", Data: []byte("[]")},
+		core.ResultItem{Name: "hw01", TimeStamp: "2020-03-01T10-00-00", Ext: "json.gz", Data: compressed},
+		core.ResultItem{Name: "hw01", TimeStamp: "2020-03-03T10-00-00", Ext: "xml.gz", Data: compressed},
+	)
+	defer cleanup()
+
+	// WHEN
+	snapshots := application.NotepadSnapshots("hw01", "json")
+	last := application.LastSnapshotTime("hw01")
+
+	// THEN
+	if len(snapshots) != 2 || snapshots[0].Ext != "json.gz" || snapshots[1].Ext != "json" {
+		t.Errorf("FAIL: Unexpected snapshots: %+v", snapshots)
+	}
+	if last.Format(core.TimestampFormat) != "2020-03-03T10-00-00" {
+		t.Errorf("FAIL: Unexpected last snapshot time: %v", last)
+	}
+}
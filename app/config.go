@@ -1,12 +1,17 @@
 package app
 
 import (
+	"bytes"
+	"fmt"
+	"github.com/pestanko/isstat/core"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v2"
 	"io/ioutil"
 	"os"
 	"path"
+	"strings"
+	"time"
 )
 
 // Config - Application config
@@ -14,8 +19,168 @@ type Config struct {
 	Muni             MuniConfig `json:"muni" yaml:"muni" mapstructure:"muni"`
 	Parser           string     `json:"parser" yaml:"parser" mapstructure:"parser"`
 	Results          string     `json:"cache" yaml:"results" mapstructure:"results"`
+	Storage          string     `json:"storage" yaml:"storage" mapstructure:"storage"`
 	DryRun           bool       `json:"dryrun" yaml:"dryrun" mapstructure:"dryrun"`
 	WithoutTimestamp bool       `json:"without_timestamp" yaml:"without_timestamp" mapstructure:"without_timestamp"`
+	Workers          int        `json:"workers" yaml:"workers" mapstructure:"workers"`
+	// Register - students register file (UCO to pseudonym mapping) shared by all runs
+	Register string `json:"register" yaml:"register" mapstructure:"register"`
+	// KontrAccounts - accounts (UCOs) the Kontr writes the notepads with, the entries edited
+	// by other accounts are flagged when parsing (the editor's account is not stored)
+	KontrAccounts []string `json:"kontr_accounts" yaml:"kontr_accounts" mapstructure:"kontr_accounts"`
+	// Pseudonym - how the students' pseudonyms are created
+	Pseudonym PseudonymConfig `json:"pseudonym" yaml:"pseudonym" mapstructure:"pseudonym"`
+	Serve     ServeConfig     `json:"serve" yaml:"serve" mapstructure:"serve"`
+	API       APIConfig       `json:"api" yaml:"api" mapstructure:"api"`
+	// Notepads - per-notepad config, the notepad shortnames are case-insensitive
+	Notepads map[string]NotepadConfig `json:"notepads" yaml:"notepads" mapstructure:"notepads"`
+	// CSV - shape of the exported CSV statistics, overridden per notepad
+	CSV core.CSVFormat `json:"csv" yaml:"csv" mapstructure:"csv"`
+	// Gradebook - rules of the grades computed across the notepads
+	Gradebook core.Gradebook `json:"gradebook" yaml:"gradebook" mapstructure:"gradebook"`
+}
+
+// NotepadConfig - per-notepad config
+type NotepadConfig struct {
+	// Deadline - RFC 3339 time or the local time in the "2006-01-02 15:04" format
+	Deadline string `json:"deadline" yaml:"deadline" mapstructure:"deadline"`
+	// FullPoints - points of the fully successful submission (default is the max points in the notepad)
+	FullPoints float64 `json:"full_points" yaml:"full_points" mapstructure:"full_points"`
+	// PassPoints - points of the passing submission (default is the half of the full points)
+	PassPoints float64 `json:"pass_points" yaml:"pass_points" mapstructure:"pass_points"`
+	// CSV - shape of the notepad's exported CSV statistics, the fields set override the global CSV config
+	CSV core.CSVFormat `json:"csv" yaml:"csv" mapstructure:"csv"`
+}
+
+// DeadlineLayout - layout of the local deadline time in the config
+const DeadlineLayout = "2006-01-02 15:04"
+
+// ParseDeadline - parses the deadline in the RFC 3339 or the DeadlineLayout (local time) format
+func ParseDeadline(value string) (time.Time, error) {
+	if deadline, err := time.Parse(time.RFC3339, value); err == nil {
+		return deadline, nil
+	}
+	return time.ParseInLocation(DeadlineLayout, value, time.Local)
+}
+
+// NotepadDeadlines - the configured deadlines keyed by the lower-cased notepad shortnames
+func (config *Config) NotepadDeadlines() (map[string]time.Time, error) {
+	deadlines := make(map[string]time.Time)
+	for notepad, notepadConfig := range config.Notepads {
+		if notepadConfig.Deadline == "" {
+			continue
+		}
+
+		deadline, err := ParseDeadline(notepadConfig.Deadline)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deadline of the notepad '%s': %v", notepad, err)
+		}
+		deadlines[strings.ToLower(notepad)] = deadline
+	}
+	return deadlines, nil
+}
+
+// NotepadDeadline - the deadline of the notepad, false if not configured
+func (config *Config) NotepadDeadline(notepad string) (time.Time, bool, error) {
+	deadlines, err := config.NotepadDeadlines()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	deadline, ok := deadlines[strings.ToLower(notepad)]
+	return deadline, ok, nil
+}
+
+// NotepadConfig - config of the notepad, the shortname is matched case-insensitively
+func (config *Config) NotepadConfig(notepad string) NotepadConfig {
+	for name, notepadConfig := range config.Notepads {
+		if strings.EqualFold(name, notepad) {
+			return notepadConfig
+		}
+	}
+	return NotepadConfig{}
+}
+
+// CSVFormat - shape of the notepad's exported CSV statistics, the global one merged with the notepad's one
+func (config *Config) CSVFormat(notepad string) core.CSVFormat {
+	return config.CSV.Merge(config.NotepadConfig(notepad).CSV)
+}
+
+// ServeConfig - daemon mode config
+type ServeConfig struct {
+	// Schedule - cron expression of the runs
+	Schedule string `json:"schedule" yaml:"schedule" mapstructure:"schedule"`
+	// History - file with the history of the runs (JSON Lines)
+	History string `json:"history" yaml:"history" mapstructure:"history"`
+	// Adaptive - fetch the notepads more often close to their deadlines (see AdaptivePolicy)
+	Adaptive bool `json:"adaptive" yaml:"adaptive" mapstructure:"adaptive"`
+	// DefaultInterval - fetch interval outside of the deadline windows
+	// (empty means DefaultAdaptiveInterval, "0s" means every run)
+	DefaultInterval string `json:"default_interval" yaml:"default_interval" mapstructure:"default_interval"`
+	// Before - fetch tiers before the deadline ("within=interval", for example "1h=1m")
+	Before []string `json:"before" yaml:"before" mapstructure:"before"`
+	// After - fetch tiers after the deadline
+	After []string `json:"after" yaml:"after" mapstructure:"after"`
+}
+
+// APIConfig - read-only REST API config
+type APIConfig struct {
+	// Listen - address the API listens on, for example "127.0.0.1:8080"
+	Listen string `json:"listen" yaml:"listen" mapstructure:"listen"`
+	// Token - bearer token required by the API, the API does not start without it unless Insecure
+	Token     string `json:"token" yaml:"token" mapstructure:"token"`
+	TokenFile string `json:"token_file" yaml:"token_file" mapstructure:"token_file"`
+	// Insecure - serve the API without the authentication when no token is configured
+	Insecure bool `json:"insecure" yaml:"insecure" mapstructure:"insecure"`
+}
+
+// LoadToken - loads the API token either from the config or from the token file
+func (config *APIConfig) LoadToken() (string, error) {
+	if config.Token != "" || config.TokenFile == "" {
+		return config.Token, nil
+	}
+
+	token, err := LoadKeyFile(config.TokenFile)
+	if err != nil {
+		return "", err
+	}
+	return string(token), nil
+}
+
+// Pseudonym modes
+const (
+	// PseudonymRandom - random UUIDs, the students register file is the only mapping
+	PseudonymRandom = "random"
+	// PseudonymHMAC - UUIDs derived from the UCO using the HMAC with the secret key
+	PseudonymHMAC = "hmac"
+)
+
+// PseudonymConfig - students' pseudonyms config
+type PseudonymConfig struct {
+	Mode    string `json:"mode" yaml:"mode" mapstructure:"mode"`
+	Key     string `json:"key" yaml:"key" mapstructure:"key"`
+	KeyFile string `json:"key_file" yaml:"key_file" mapstructure:"key_file"`
+}
+
+// LoadKey - loads the pseudonym key either from the config or from the key file
+func (config *PseudonymConfig) LoadKey() ([]byte, error) {
+	if config.Key != "" {
+		return []byte(config.Key), nil
+	}
+
+	if config.KeyFile == "" {
+		return nil, fmt.Errorf("pseudonym key nor key file is configured")
+	}
+
+	return LoadKeyFile(config.KeyFile)
+}
+
+// LoadKeyFile - loads the pseudonym key from the file, the surrounding whitespace is ignored
+func LoadKeyFile(file string) ([]byte, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(content), nil
 }
 
 //MuniConfig - Is muni config
@@ -24,10 +189,25 @@ type MuniConfig struct {
 	Token   string `json:"token" yaml:"token" mapstructure:"token"`
 	Course  string `json:"course" yaml:"course" mapstructure:"course"`
 	Faculty int    `json:"faculty_id" yaml:"faculty" mapstructure:"faculty"`
+	// Timeout of one request in seconds
+	Timeout int `json:"timeout" yaml:"timeout" mapstructure:"timeout"`
+	// Retries of the failed requests
+	Retries int `json:"retries" yaml:"retries" mapstructure:"retries"`
+	// RateLimit - max requests per second to the IS (0 = unlimited)
+	RateLimit float64 `json:"rate_limit" yaml:"rate_limit" mapstructure:"rate_limit"`
 }
 
 const IsStatConfigName = "isstat-config"
 
+// RunHistoryName - default name of the daemon runs history file in the app config dir
+const RunHistoryName = "runs.jsonl"
+
+// DefaultResultsDatabase - default results database file (in the working directory) of the bolt storage
+const DefaultResultsDatabase = "isstat-results.db"
+
+// StudentsRegisterName - default name of the students register file in the app config dir
+const StudentsRegisterName = "students-register.json"
+
 // Gets the application configuration directory
 func GetAppConfigDir() (string, error) {
 	configDir, err := os.UserConfigDir()
@@ -123,6 +303,10 @@ func GetAppConfig() (Config, error) {
 		return config, err
 	}
 
+	if config.Results == "" && config.Storage == core.BoltStoreKind {
+		config.Results = DefaultResultsDatabase
+	}
+
 	if config.Results == "" {
 		var err error
 		config.Results, err = os.Getwd()
@@ -132,6 +316,24 @@ func GetAppConfig() (Config, error) {
 		}
 	}
 
+	if config.Register == "" {
+		appConfigDir, err := GetAppConfigDir()
+		if err != nil {
+			log.WithError(err).Warning("Unable to get the application config directory")
+			return config, err
+		}
+		config.Register = path.Join(appConfigDir, StudentsRegisterName)
+	}
+
+	if config.Serve.History == "" {
+		appConfigDir, err := GetAppConfigDir()
+		if err != nil {
+			log.WithError(err).Warning("Unable to get the application config directory")
+			return config, err
+		}
+		config.Serve.History = path.Join(appConfigDir, RunHistoryName)
+	}
+
 	return config, nil
 }
 
@@ -139,6 +341,14 @@ func setDefaults() {
 	viper.SetDefault("muni.url", "https://is.muni.cz")
 	viper.SetDefault("muni.course", "PB071")
 	viper.SetDefault("muni.faculty", 1433)
+	viper.SetDefault("muni.timeout", int(core.DefaultTimeout/time.Second))
+	viper.SetDefault("muni.retries", core.DefaultRetries)
+	viper.SetDefault("muni.rate_limit", core.DefaultRateLimit)
 	viper.SetDefault("parser", "default")
 	viper.SetDefault("dryrun", false)
+	viper.SetDefault("workers", 4)
+	viper.SetDefault("storage", core.FilesystemStoreKind)
+	viper.SetDefault("pseudonym.mode", PseudonymRandom)
+	viper.SetDefault("serve.schedule", "*/15 * * * *")
+	viper.SetDefault("api.listen", "127.0.0.1:8080")
 }
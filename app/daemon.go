@@ -0,0 +1,292 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	log "github.com/sirupsen/logrus"
+)
+
+// Run statuses
+const (
+	RunOK      = "ok"
+	RunPartial = "partial"
+	RunFailed  = "failed"
+	RunSkipped = "skipped"
+)
+
+// RunRecord - one scheduled run of the pipeline
+type RunRecord struct {
+	Start    time.Time    `json:"start"`
+	End      time.Time    `json:"end"`
+	Status   string       `json:"status"`
+	Notepads int          `json:"notepads"`
+	Failed   int          `json:"failed"`
+	Error    string       `json:"error,omitempty"`
+	Results  []SyncResult `json:"results,omitempty"`
+}
+
+// RunHistory - history of the runs stored as JSON Lines
+type RunHistory struct {
+	File string
+	mu   sync.Mutex
+}
+
+// NewRunHistory - creates the history stored in the file
+func NewRunHistory(file string) *RunHistory {
+	return &RunHistory{File: file}
+}
+
+// Append - appends the record to the history
+func (history *RunHistory) Append(record *RunRecord) error {
+	history.mu.Lock()
+	defer history.mu.Unlock()
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(history.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Load - loads the last limit records (all of them for limit <= 0), ordered from the oldest
+func (history *RunHistory) Load(limit int) ([]RunRecord, error) {
+	history.mu.Lock()
+	defer history.mu.Unlock()
+
+	file, err := os.Open(history.File)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []RunRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var record RunRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			log.WithError(err).WithField("file", history.File).Warning("Skipping invalid run record")
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return records, err
+	}
+
+	if limit > 0 && len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+	return records, nil
+}
+
+// Daemon - runs the sync pipeline on the schedule
+type Daemon struct {
+	Schedule cron.Schedule
+	// Notepads - resolves the notepads before each run
+	Notepads func() ([]string, error)
+	// Pipeline - the pipeline run for the notepads
+	Pipeline func(notepads []string) ([]SyncResult, error)
+	History  *RunHistory
+	// Policy - when set, only the due notepads are synced in each run
+	Policy *AdaptivePolicy
+	// LastFetch - time of the last stored snapshot of the notepad, used before the daemon fetches it itself
+	LastFetch func(notepad string) time.Time
+
+	running   int32
+	wg        sync.WaitGroup
+	mu        sync.Mutex
+	lastFetch map[string]time.Time
+}
+
+// NewDaemon - creates the daemon running the application's sync pipeline on the cron schedule
+func NewDaemon(app *IsStatApp, spec string, notepads func() ([]string, error), history *RunHistory) (*Daemon, error) {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Daemon{
+		Schedule: schedule,
+		Notepads: notepads,
+		Pipeline: app.Sync,
+		History:  history,
+	}, nil
+}
+
+// Run - runs the pipeline on the schedule until the context is cancelled
+//
+// The run in progress is finished before returning.
+func (daemon *Daemon) Run(ctx context.Context, immediately bool) {
+	if immediately {
+		daemon.Trigger()
+	}
+
+	for {
+		next := daemon.Schedule.Next(time.Now())
+		log.WithField("next", next).Info("Next run scheduled")
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			log.Info("Shutting down, waiting for the running run")
+			daemon.wg.Wait()
+			return
+		case <-timer.C:
+			daemon.Trigger()
+		}
+	}
+}
+
+// Trigger - starts the run in the background, the run is skipped if the previous one is still running
+//
+// It returns false when the run was skipped.
+func (daemon *Daemon) Trigger() bool {
+	if !atomic.CompareAndSwapInt32(&daemon.running, 0, 1) {
+		log.Warning("Previous run is still running, skipping")
+		now := time.Now()
+		daemon.record(&RunRecord{Start: now, End: now, Status: RunSkipped, Error: "previous run is still running"})
+		return false
+	}
+
+	daemon.wg.Add(1)
+	go func() {
+		defer daemon.wg.Done()
+		defer atomic.StoreInt32(&daemon.running, 0)
+
+		record := daemon.runOnce()
+		daemon.record(&record)
+	}()
+	return true
+}
+
+// Wait - waits for the running run
+func (daemon *Daemon) Wait() {
+	daemon.wg.Wait()
+}
+
+func (daemon *Daemon) runOnce() (record RunRecord) {
+	record.Start = time.Now()
+	defer func() {
+		record.End = time.Now()
+		log.WithFields(log.Fields{
+			"status":   record.Status,
+			"notepads": record.Notepads,
+			"failed":   record.Failed,
+			"duration": record.End.Sub(record.Start),
+		}).Info("Run finished")
+	}()
+
+	notepads, err := daemon.Notepads()
+	if err != nil {
+		record.Status = RunFailed
+		record.Error = err.Error()
+		return record
+	}
+
+	notepads = daemon.dueNotepads(notepads, record.Start)
+	if len(notepads) == 0 {
+		record.Status = RunSkipped
+		return record
+	}
+
+	results, err := daemon.Pipeline(notepads)
+	daemon.updateLastFetch(results, record.Start)
+	record.Notepads = len(notepads)
+	record.Results = results
+	for i := range results {
+		if results[i].Failed() {
+			record.Failed++
+		}
+	}
+
+	switch {
+	case err == nil:
+		record.Status = RunOK
+	case record.Failed > 0 && record.Failed < len(results):
+		record.Status = RunPartial
+		record.Error = err.Error()
+	default:
+		record.Status = RunFailed
+		record.Error = err.Error()
+	}
+	return record
+}
+
+// dueNotepads - the notepads to be synced by the policy, all of them without the policy
+func (daemon *Daemon) dueNotepads(notepads []string, now time.Time) []string {
+	if daemon.Policy == nil {
+		return notepads
+	}
+
+	daemon.mu.Lock()
+	defer daemon.mu.Unlock()
+
+	var due []string
+	for _, notepad := range notepads {
+		last, ok := daemon.lastFetch[notepad]
+		if !ok && daemon.LastFetch != nil {
+			last = daemon.LastFetch(notepad)
+		}
+
+		if daemon.Policy.Due(notepad, last, now) {
+			due = append(due, notepad)
+		} else {
+			log.WithFields(log.Fields{
+				"notepad":  notepad,
+				"last":     last,
+				"interval": daemon.Policy.Interval(notepad, now),
+			}).Debug("Notepad is not due")
+		}
+	}
+	return due
+}
+
+func (daemon *Daemon) updateLastFetch(results []SyncResult, start time.Time) {
+	daemon.mu.Lock()
+	defer daemon.mu.Unlock()
+
+	if daemon.lastFetch == nil {
+		daemon.lastFetch = make(map[string]time.Time)
+	}
+	for _, result := range results {
+		if result.Fetch == StepOK {
+			daemon.lastFetch[result.Notepad] = start
+		}
+	}
+}
+
+func (daemon *Daemon) record(record *RunRecord) {
+	if daemon.History == nil {
+		return
+	}
+
+	// nothing was due - not worth the record
+	if record.Status == RunSkipped && record.Error == "" {
+		return
+	}
+	if err := daemon.History.Append(record); err != nil {
+		log.WithError(err).WithField("file", daemon.History.File).Error("Unable to store the run record")
+	}
+}
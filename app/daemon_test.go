@@ -0,0 +1,91 @@
+package app
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+func newTestDaemon(t *testing.T, pipeline func([]string) ([]SyncResult, error)) (*Daemon, func()) {
+	dir, err := ioutil.TempDir("", "isstat-daemon")
+	if err != nil {
+		t.Fatalf("FAIL: Unable to create temp dir: %v", err)
+	}
+
+	daemon := &Daemon{
+		Notepads: func() ([]string, error) { return []string{"hw01", "hw02"}, nil },
+		Pipeline: pipeline,
+		History:  NewRunHistory(path.Join(dir, "runs.jsonl")),
+	}
+	return daemon, func() { _ = os.RemoveAll(dir) }
+}
+
+func TestDaemon_SkipsOverlappingRuns(t *testing.T) {
+	// GIVEN
+	release := make(chan struct{})
+	daemon, cleanup := newTestDaemon(t, func(notepads []string) ([]SyncResult, error) {
+		<-release
+		return []SyncResult{{Notepad: notepads[0]}, {Notepad: notepads[1]}}, nil
+	})
+	defer cleanup()
+
+	// WHEN
+	first := daemon.Trigger()
+	second := daemon.Trigger()
+	close(release)
+	daemon.Wait()
+
+	// THEN
+	if !first || second {
+		t.Errorf("FAIL: First run should start (%v) and the second one be skipped (%v)", first, second)
+	}
+
+	records, err := daemon.History.Load(0)
+	if err != nil {
+		t.Fatalf("FAIL: Unable to load the history: %v", err)
+	}
+	if len(records) != 2 || records[0].Status != RunSkipped || records[1].Status != RunOK {
+		t.Errorf("FAIL: Unexpected history: %v", records)
+	}
+	if records[1].Notepads != 2 {
+		t.Errorf("FAIL: Run notepads is %d, expected: %d", records[1].Notepads, 2)
+	}
+}
+
+func TestDaemon_PartialFailure(t *testing.T) {
+	// GIVEN
+	daemon, cleanup := newTestDaemon(t, func(notepads []string) ([]SyncResult, error) {
+		return []SyncResult{{Notepad: "hw01"}, {Notepad: "hw02", Error: "boom"}}, errors.New("sync failed")
+	})
+	defer cleanup()
+
+	// WHEN
+	daemon.Trigger()
+	daemon.Wait()
+
+	// THEN
+	records, _ := daemon.History.Load(1)
+	if len(records) != 1 || records[0].Status != RunPartial || records[0].Failed != 1 {
+		t.Errorf("FAIL: Unexpected history: %v", records)
+	}
+}
+
+func TestRunHistory_LoadLast(t *testing.T) {
+	// GIVEN
+	daemon, cleanup := newTestDaemon(t, nil)
+	defer cleanup()
+	for i := 0; i < 5; i++ {
+		_ = daemon.History.Append(&RunRecord{Start: time.Unix(int64(i), 0), Status: RunOK})
+	}
+
+	// WHEN
+	records, err := daemon.History.Load(2)
+
+	// THEN
+	if err != nil || len(records) != 2 || records[1].Start.Unix() != 4 {
+		t.Errorf("FAIL: Unexpected records: %v (%v)", records, err)
+	}
+}
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var dashboardFuncs = template.FuncMap{
+	"percent": func(share float64) string {
+		return fmt.Sprintf("%.0f %%", share*100)
+	},
+	"deadline": func(deadline time.Time) string {
+		if deadline.IsZero() {
+			return "-"
+		}
+		return deadline.Format(DeadlineLayout)
+	},
+	"notepadURL": func(static bool, notepad string) string {
+		if static {
+			return reportPageName(notepad)
+		}
+		return "/dashboard/" + notepad
+	},
+	"css": func() template.CSS {
+		return template.CSS(dashboardCSS)
+	},
+	// svg - the charts are rendered by the charts package, which escapes all the texts
+	"svg": func(svg string) template.HTML {
+		return template.HTML(svg)
+	},
+}
+
+var (
+	dashboardIndexTemplate   = template.Must(template.Must(template.New("index").Funcs(dashboardFuncs).Parse(dashboardLayout)).Parse(dashboardIndex))
+	dashboardNotepadTemplate = template.Must(template.Must(template.New("notepad").Funcs(dashboardFuncs).Parse(dashboardLayout)).Parse(dashboardNotepad))
+)
+
+// registerDashboard - adds the dashboard pages to the API mux
+//
+//	GET /                     - notepads overview
+//	GET /dashboard/{notepad}  - charts of the notepad
+func (server *APIServer) registerDashboard(mux *http.ServeMux) {
+	mux.HandleFunc("/", server.handleDashboardIndex)
+	mux.HandleFunc("/dashboard/", server.handleDashboardNotepad)
+	mux.HandleFunc("/assets/dashboard.css", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css; charset=utf-8")
+		_, _ = w.Write([]byte(dashboardCSS))
+	})
+}
+
+func (server *APIServer) handleDashboardIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	var notepads []*NotepadProgress
+	for _, notepad := range server.App.ProgressNotepads() {
+		progress, err := server.App.NotepadProgress(notepad)
+		if err != nil {
+			log.WithError(err).WithField("notepad", notepad).Warning("Unable to compute the notepad progress")
+			continue
+		}
+		notepads = append(notepads, &progress)
+	}
+
+	renderDashboard(w, dashboardIndexTemplate, map[string]interface{}{
+		"Title":    "Notepads",
+		"Notepads": notepads,
+		"Static":   false,
+	})
+}
+
+func (server *APIServer) handleDashboardNotepad(w http.ResponseWriter, r *http.Request) {
+	notepad := strings.TrimPrefix(r.URL.Path, "/dashboard/")
+	if notepad == "" || strings.Contains(notepad, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	progress, err := server.App.NotepadProgress(notepad)
+	if err != nil {
+		log.WithError(err).WithField("notepad", notepad).Warning("Unable to compute the notepad progress")
+		http.NotFound(w, r)
+		return
+	}
+
+	renderDashboard(w, dashboardNotepadTemplate, map[string]interface{}{
+		"Title":    notepad,
+		"Progress": &progress,
+		"Static":   false,
+	})
+}
+
+func renderDashboard(w http.ResponseWriter, tmpl *template.Template, data interface{}) {
+	page, err := executeDashboard(tmpl, data)
+	if err != nil {
+		log.WithError(err).Error("Unable to render the dashboard")
+		http.Error(w, "unable to render the dashboard", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(page)
+}
+
+func executeDashboard(tmpl *template.Template, data interface{}) ([]byte, error) {
+	var page bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&page, "layout", data); err != nil {
+		return nil, err
+	}
+	return page.Bytes(), nil
+}
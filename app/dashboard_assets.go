@@ -0,0 +1,71 @@
+package app
+
+// The dashboard assets are compiled into the binary, so the dashboard works without any files next to it.
+// The same templates render the static report (Static = true), which inlines the styles and links the pages relatively.
+
+const dashboardCSS = `
+body { font-family: sans-serif; margin: 0; color: #222; background: #fafafa; }
+header { background: #0b3d91; color: #fff; padding: 12px 24px; }
+header a { color: #fff; text-decoration: none; }
+main { padding: 16px 24px; }
+table { border-collapse: collapse; background: #fff; }
+th, td { padding: 6px 12px; border-bottom: 1px solid #e5e5e5; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+.charts { display: flex; flex-wrap: wrap; gap: 16px; }
+.charts svg { background: #fff; border: 1px solid #e5e5e5; max-width: 100%; height: auto; }
+.muted { color: #777; }
+footer { padding: 8px 24px; font-size: 12px; }
+`
+
+const dashboardLayout = `{{define "layout"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} - isstat</title>
+{{if .Static}}<style>{{css}}</style>{{else}}<link rel="stylesheet" href="/assets/dashboard.css">{{end}}
+</head>
+<body>
+<header><a href="{{if .Static}}index.html{{else}}/{{end}}">isstat</a> / {{.Title}}</header>
+<main>{{template "content" .}}</main>
+{{if .Static}}<footer class="muted">Generated {{.Generated.Format "2006-01-02 15:04"}}</footer>{{end}}
+</body>
+</html>{{end}}`
+
+const dashboardIndex = `{{define "content"}}
+{{if not .Notepads}}<p class="muted">No parsed notepads yet, run "isstat sync" or "isstat parse" first.</p>{{else}}
+<table>
+<tr><th>Notepad</th><th>Snapshot</th><th>Students</th><th>Submitted</th><th>Finished</th><th>Mean points</th><th>Deadline</th></tr>
+{{range .Notepads}}<tr>
+<td><a href="{{notepadURL $.Static .Name}}">{{.Name}}</a></td>
+<td>{{.Summary.TimeStamp}}</td>
+<td>{{.Summary.Students}}</td>
+<td>{{.Summary.Submitted}}</td>
+<td>{{percent .Summary.FinalShare}}</td>
+<td>{{printf "%.2f" .Summary.Points.Mean}}</td>
+<td>{{deadline .Deadline}}</td>
+</tr>{{end}}
+</table>{{end}}
+{{end}}`
+
+const dashboardNotepad = `{{define "content"}}
+<table>
+<tr><th>Snapshot</th><th>Students</th><th>Submitted</th><th>Submissions</th><th>Finished</th><th>Mean points</th><th>Median points</th><th>Deadline</th></tr>
+<tr>
+<td>{{.Progress.Summary.TimeStamp}}</td>
+<td>{{.Progress.Summary.Students}}</td>
+<td>{{.Progress.Summary.Submitted}}</td>
+<td>{{.Progress.Summary.Submissions}}</td>
+<td>{{percent .Progress.Summary.FinalShare}}</td>
+<td>{{printf "%.2f" .Progress.Summary.Points.Mean}}</td>
+<td>{{printf "%.2f" .Progress.Summary.Points.Median}}</td>
+<td>{{deadline .Progress.Deadline}}</td>
+</tr>
+</table>
+<div class="charts">
+{{svg .Progress.ScoreChart}}
+{{svg .Progress.SubmissionsChart}}
+{{svg .Progress.CumulativeChart}}
+{{svg .Progress.FinishedChart}}
+</div>
+{{end}}`
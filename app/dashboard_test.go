@@ -0,0 +1,90 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDashboard_Pages(t *testing.T) {
+	// GIVEN
+	server, cleanup := newTestAPI(t)
+	defer cleanup()
+
+	// WHEN
+	indexResponse, index := apiGet(t, server, "/", testAPIToken)
+	notepadResponse, notepad := apiGet(t, server, "/dashboard/hw01", testAPIToken)
+	missing, _ := apiGet(t, server, "/dashboard/hw02", testAPIToken)
+
+	// THEN
+	if indexResponse.StatusCode != http.StatusOK || !strings.Contains(index, `href="/dashboard/hw01"`) {
+		t.Errorf("FAIL: Index should link the parsed notepad: %d %s", indexResponse.StatusCode, index)
+	}
+	if strings.Contains(index, "/dashboard/hw02") {
+		t.Errorf("FAIL: Index should not link the notepad without the parsed snapshots")
+	}
+	if notepadResponse.StatusCode != http.StatusOK || strings.Count(notepad, "<svg") != 4 {
+		t.Errorf("FAIL: Notepad page should contain 4 charts: %d %s", notepadResponse.StatusCode, notepad)
+	}
+	if missing.StatusCode != http.StatusNotFound {
+		t.Errorf("FAIL: Notepad without the parsed snapshots should not be found: %d", missing.StatusCode)
+	}
+}
+
+func TestDashboard_TokenCookie(t *testing.T) {
+	// GIVEN
+	server, cleanup := newTestAPI(t)
+	defer cleanup()
+
+	// WHEN
+	client := server.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	response, err := client.Get(server.URL + "/dashboard/hw01?token=" + testAPIToken + "&range=all")
+	if err != nil {
+		t.Fatalf("FAIL: Request failed: %v", err)
+	}
+	_ = response.Body.Close()
+
+	// THEN
+	if response.StatusCode != http.StatusSeeOther || response.Header.Get("Location") != "/dashboard/hw01?range=all" {
+		t.Fatalf("FAIL: Token in the query should redirect to the URL without it: %d %s",
+			response.StatusCode, response.Header.Get("Location"))
+	}
+
+	var cookie *http.Cookie
+	for _, c := range response.Cookies() {
+		if c.Name == apiTokenCookie {
+			cookie = c
+		}
+	}
+	if cookie == nil || !cookie.HttpOnly || cookie.Secure || cookie.SameSite != http.SameSiteStrictMode {
+		t.Fatalf("FAIL: Token should be kept in the HttpOnly and SameSite cookie, not Secure on HTTP: %v", response.Cookies())
+	}
+
+	proxied, _ := http.NewRequest(http.MethodGet, server.URL+"/dashboard/hw01?token="+testAPIToken, nil)
+	proxied.Header.Set("X-Forwarded-Proto", "https")
+	proxiedResponse, err := client.Do(proxied)
+	if err != nil {
+		t.Fatalf("FAIL: Request failed: %v", err)
+	}
+	_ = proxiedResponse.Body.Close()
+	if cookies := proxiedResponse.Cookies(); len(cookies) != 1 || !cookies[0].Secure {
+		t.Errorf("FAIL: Token cookie behind the HTTPS proxy should be Secure: %v", cookies)
+	}
+
+	invalid, _ := apiGet(t, server, "/?token=wrong", "")
+	if invalid.StatusCode != http.StatusUnauthorized {
+		t.Errorf("FAIL: Invalid token in the query should be unauthorized: %d", invalid.StatusCode)
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, server.URL+"/dashboard/hw01", nil)
+	request.AddCookie(cookie)
+	withCookie, err := client.Do(request)
+	if err != nil {
+		t.Fatalf("FAIL: Request failed: %v", err)
+	}
+	_ = withCookie.Body.Close()
+	if withCookie.StatusCode != http.StatusOK {
+		t.Errorf("FAIL: Request with the cookie should be authorized: %d", withCookie.StatusCode)
+	}
+}
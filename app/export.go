@@ -0,0 +1,92 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pestanko/isstat/core"
+	"github.com/pestanko/isstat/xlsx"
+)
+
+// ExportXLSX - export format of the Excel workbook
+const ExportXLSX = "xlsx"
+
+// summarySheet - name of the workbook's first sheet
+const summarySheet = "Summary"
+
+// ExportWorkbook - workbook of the latest parsed snapshots of the notepads matching any of the glob patterns (all for no patterns)
+//
+// The summary sheet with one row per notepad is followed by one sheet per notepad with one row per submission.
+// The submission times are in the local time.
+func (app *IsStatApp) ExportWorkbook(patterns []string) (xlsx.Workbook, error) {
+	snapshots, err := app.latestSnapshots(patterns)
+	if err != nil {
+		return xlsx.Workbook{}, err
+	}
+	if len(snapshots) == 0 {
+		return xlsx.Workbook{}, fmt.Errorf("no parsed notepads matching %v", patterns)
+	}
+
+	summary := xlsx.Sheet{
+		Name: summarySheet,
+		Header: []string{"notepad", "snapshot", "students", "submitted", "submissions", "finished",
+			"points_mean", "points_median", "points_stddev", "points_min", "points_max", "bonus_mean", "deadline"},
+	}
+	var sheets []xlsx.Sheet
+
+	for _, snapshot := range snapshots {
+		stats := core.ComputeNotepadSummary(snapshot.Name, snapshot.TimeStamp, snapshot.Students)
+
+		deadline := xlsx.Cell{}
+		if app.Config != nil {
+			value, ok, err := app.Config.NotepadDeadline(snapshot.Name)
+			if err != nil {
+				return xlsx.Workbook{}, err
+			}
+			if ok {
+				deadline = xlsx.DateTime(value.In(time.Local))
+			}
+		}
+
+		summary.Rows = append(summary.Rows, []xlsx.Cell{
+			xlsx.String(snapshot.Name),
+			xlsx.String(snapshot.TimeStamp),
+			xlsx.Number(float64(stats.Students)),
+			xlsx.Number(float64(stats.Submitted)),
+			xlsx.Number(float64(stats.Submissions)),
+			xlsx.Percent(stats.FinalShare),
+			xlsx.Number(stats.Points.Mean),
+			xlsx.Number(stats.Points.Median),
+			xlsx.Number(stats.Points.StdDev),
+			xlsx.Number(stats.Points.Min),
+			xlsx.Number(stats.Points.Max),
+			xlsx.Number(stats.Bonus.Mean),
+			deadline,
+		})
+
+		sheets = append(sheets, notepadSheet(&snapshot))
+	}
+
+	return xlsx.Workbook{Sheets: append([]xlsx.Sheet{summary}, sheets...)}, nil
+}
+
+// notepadSheet - one row per submission of the notepad's students
+func notepadSheet(snapshot *latestSnapshot) xlsx.Sheet {
+	sheet := xlsx.Sheet{
+		Name:   snapshot.Name,
+		Header: []string{"student_id", "index", "submitted", "points", "bonus", "final"},
+	}
+	for _, student := range snapshot.Students {
+		for _, submission := range student.Submissions {
+			sheet.Rows = append(sheet.Rows, []xlsx.Cell{
+				xlsx.String(student.ID.String()),
+				xlsx.Number(float64(submission.Index)),
+				xlsx.DateTime(submission.DateTime.In(time.Local)),
+				xlsx.Number(submission.Points),
+				xlsx.Number(submission.Bonus),
+				xlsx.Bool(submission.Final),
+			})
+		}
+	}
+	return sheet
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/pestanko/isstat/core"
+)
+
+func TestExportWorkbook(t *testing.T) {
+	// GIVEN
+	students := testStudents(`{"index":0,"datetime":"2020-03-07T10:00:00Z","points":4},` +
+		`{"index":1,"datetime":"2020-03-08T10:00:00Z","points":9,"final":true}`)
+	application, cleanup := newResultsTestApp(t,
+		core.ResultItem{Name: "hw01", TimeStamp: "2020-03-01T10-00-00", Ext: "json", Data: []byte("[]")},
+		core.ResultItem{Name: "hw01", TimeStamp: "2020-03-09T10-00-00", Ext: "json", Data: students},
+		core.ResultItem{Name: "exam", TimeStamp: "2020-03-09T10-00-00", Ext: "json", Data: students},
+	)
+	defer cleanup()
+	application.Config = &Config{Notepads: map[string]NotepadConfig{
+		"hw01": {Deadline: "2020-03-08T23:59:00Z"},
+	}}
+
+	// WHEN
+	workbook, err := application.ExportWorkbook([]string{"hw*"})
+
+	// THEN
+	if err != nil {
+		t.Fatalf("FAIL: Unable to export: %v", err)
+	}
+	if len(workbook.Sheets) != 2 || workbook.Sheets[0].Name != summarySheet || workbook.Sheets[1].Name != "hw01" {
+		t.Fatalf("FAIL: Expected the summary and the hw01 sheets, got %+v", workbook.Sheets)
+	}
+	if len(workbook.Sheets[0].Rows) != 1 || len(workbook.Sheets[0].Rows[0]) != len(workbook.Sheets[0].Header) {
+		t.Errorf("FAIL: Expected one summary row of the header's width, got %+v", workbook.Sheets[0].Rows)
+	}
+	if len(workbook.Sheets[1].Rows) != 2 {
+		t.Errorf("FAIL: Expected one row per submission of the latest snapshot, got %+v", workbook.Sheets[1].Rows)
+	}
+	if err := workbook.Write(ioutil.Discard); err != nil {
+		t.Errorf("FAIL: Unable to write the workbook: %v", err)
+	}
+}
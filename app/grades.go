@@ -0,0 +1,44 @@
+package app
+
+import (
+	"github.com/pestanko/isstat/core"
+	log "github.com/sirupsen/logrus"
+)
+
+// Grades - computes the students' grades from the latest parsed snapshots of the gradebook's notepads
+//
+// The notepad without the max points in the gradebook uses its configured full points (see NotepadConfig).
+// The notepad without any parsed snapshot is graded as missing for all the students.
+func (app *IsStatApp) Grades(gradebook core.Gradebook) ([]core.StudentGrade, error) {
+	if err := gradebook.Validate(); err != nil {
+		return nil, err
+	}
+
+	notepads := make([]core.GradebookNotepad, len(gradebook.Notepads))
+	copy(notepads, gradebook.Notepads)
+	gradebook.Notepads = notepads
+
+	students := make(map[string][]core.StudentInfo)
+	for i := range gradebook.Notepads {
+		notepad := &gradebook.Notepads[i]
+		if notepad.MaxPoints == 0 && app.Config != nil {
+			notepad.MaxPoints = app.Config.NotepadConfig(notepad.Name).FullPoints
+		}
+
+		entry := log.WithField("notepad", notepad.Name)
+		snapshots := app.NotepadSnapshots(notepad.Name, "json")
+		if len(snapshots) == 0 {
+			entry.Warning("No parsed snapshot, the notepad is missing for all the students")
+			continue
+		}
+
+		latest := snapshots[len(snapshots)-1]
+		infos, err := app.readStudentInfo(&latest)
+		if err != nil {
+			return nil, err
+		}
+		students[notepad.Name] = infos
+	}
+
+	return gradebook.Grade(students)
+}
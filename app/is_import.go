@@ -0,0 +1,53 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/pestanko/isstat/core"
+	log "github.com/sirupsen/logrus"
+)
+
+// ISImport - prepares the validated IS bulk import of the students' grades
+//
+// The students are mapped back to their UCOs by the configured students register,
+// so only the users holding the register can prepare the import. When the notepad is provided,
+// its current content is read from the IS and the UCOs not present in the notepad are reported as unknown.
+func (app *IsStatApp) ISImport(gradebook core.Gradebook, value string, allowed []string, notepad string) (core.ISImport, error) {
+	if app.Config == nil || app.Config.Register == "" {
+		return core.ISImport{}, fmt.Errorf("the students register is required to map the students to their UCOs")
+	}
+
+	register := core.NewStudentsRegister()
+	if err := register.Import(app.Config.Register); err != nil {
+		return core.ISImport{}, fmt.Errorf("unable to load the students register: %v", err)
+	}
+
+	grades, err := app.Grades(gradebook)
+	if err != nil {
+		return core.ISImport{}, err
+	}
+
+	isImport, err := core.NewISImport(grades, &register, value)
+	if err != nil {
+		return isImport, err
+	}
+
+	var known map[string]bool
+	if notepad != "" {
+		reader := app.Client
+		reader.DryRun = false
+		current, err := reader.GetNotepadContent(notepad)
+		if err != nil {
+			log.WithField("name", notepad).WithError(err).Error("Unable to fetch the current content")
+			return isImport, err
+		}
+
+		known = make(map[string]bool)
+		for _, student := range current.StudentsContent {
+			known[student.Uco] = true
+		}
+	}
+
+	isImport.Validate(allowed, known)
+	return isImport, nil
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pestanko/isstat/core"
+	"github.com/pestanko/isstat/parsers"
+)
+
+const parseTestNotepad = `<BLOKY_OBSAH><STUDENT><UCO>123456</UCO><ZMENIL>654321</ZMENIL><OBSAH>
+%%       datum    cas  body
+ 1  2020-02-18  08:45    *1
+</OBSAH></STUDENT></BLOKY_OBSAH>`
+
+func newParseTestApp(t *testing.T, dryRun bool) (*IsStatApp, func()) {
+	dir, err := ioutil.TempDir("", "isstat-parse")
+	if err != nil {
+		t.Fatalf("FAIL: Unable to create temp dir: %v", err)
+	}
+
+	register := core.NewStudentsRegister()
+	application := &IsStatApp{
+		Parser: &parsers.BasicParser{StudentsRegister: &register, NotepadContentParser: &parsers.KontrFunctionalityParser{},
+			KontrAccounts: []string{"1000"}},
+		Results:  core.NewResults(dir, false),
+		Register: &register,
+		Config:   &Config{DryRun: dryRun},
+	}
+
+	item := core.ResultItem{Name: "hw01", TimeStamp: "2020-03-01T10-00-00", Ext: "xml", Data: []byte(parseTestNotepad)}
+	if err := application.Results.Store(&item); err != nil {
+		t.Fatalf("FAIL: Unable to store the result: %v", err)
+	}
+	return application, func() { _ = os.RemoveAll(dir) }
+}
+
+func TestParseOne_StoresJSON(t *testing.T) {
+	// GIVEN
+	application, cleanup := newParseTestApp(t, false)
+	defer cleanup()
+
+	// WHEN
+	students, err := application.ParseOne("hw01.2020-03-01T10-00-00.xml")
+
+	// THEN
+	if err != nil || len(students) != 1 || len(students[0].Submissions) != 1 {
+		t.Fatalf("FAIL: Unexpected parsed students %+v (%v)", students, err)
+	}
+	if !students[0].EditedByOther {
+		t.Errorf("FAIL: Expected the entry edited by other than the Kontr account")
+	}
+	snapshots := application.NotepadSnapshots("hw01", "json")
+	if len(snapshots) != 1 {
+		t.Fatalf("FAIL: Expected the stored json snapshot, got %+v", snapshots)
+	}
+	if content, _ := application.Results.GetContent(&snapshots[0]); strings.Contains(string(content), "654321") {
+		t.Errorf("FAIL: The editor's UCO is stored in the parsed notepad: %s", content)
+	}
+}
+
+func TestParseOne_DryRunDoesNotStore(t *testing.T) {
+	// GIVEN
+	application, cleanup := newParseTestApp(t, true)
+	defer cleanup()
+
+	// WHEN
+	students, err := application.ParseOne("hw01.2020-03-01T10-00-00.xml")
+
+	// THEN
+	if err != nil || len(students) != 1 {
+		t.Fatalf("FAIL: Unexpected parsed students %+v (%v)", students, err)
+	}
+	if snapshots := application.NotepadSnapshots("hw01", "json"); len(snapshots) != 0 {
+		t.Errorf("FAIL: Expected no json snapshot with the unsaved pseudonyms in the dry run, got %+v", snapshots)
+	}
+}
@@ -0,0 +1,186 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pestanko/isstat/charts"
+	"github.com/pestanko/isstat/core"
+	log "github.com/sirupsen/logrus"
+)
+
+// scoreBins - max number of the buckets of the score distribution
+const scoreBins = 10
+
+// NotepadProgress - progress of the notepad computed from its parsed snapshots
+type NotepadProgress struct {
+	Name string `json:"name"`
+	// Summary - summary of the latest snapshot
+	Summary core.NotepadSummary `json:"summary"`
+	// Deadline - the configured deadline, zero when not configured
+	Deadline time.Time `json:"deadline"`
+	// Scores - distribution of the final submissions' points in the latest snapshot
+	Scores []core.HistogramBucket `json:"scores"`
+	// Attempts - distribution of the number of the students' submissions in the latest snapshot
+	Attempts []core.HistogramBucket `json:"attempts"`
+	// Timeline - submissions per day in the latest snapshot
+	Timeline []core.TimelinePoint `json:"timeline"`
+	// Lateness - submissions after the deadline in the latest snapshot, nil without the deadline
+	Lateness *core.Lateness `json:"lateness,omitempty"`
+	// History - summaries of all the snapshots, ordered from the oldest
+	History []core.NotepadSummary `json:"history"`
+}
+
+// ProgressNotepads - names of the notepads with the parsed snapshots
+func (app *IsStatApp) ProgressNotepads() []string {
+	names := make(map[string]bool)
+	for _, item := range app.PatternsToResultItems([]string{"*.json*"}) {
+		if item.BaseExt() == "json" {
+			names[item.Name] = true
+		}
+	}
+
+	var notepads []string
+	for name := range names {
+		notepads = append(notepads, name)
+	}
+	sort.Strings(notepads)
+	return notepads
+}
+
+// latestSnapshot - students of the latest parsed snapshot of the notepad
+type latestSnapshot struct {
+	Name      string
+	TimeStamp string
+	Students  []core.StudentInfo
+}
+
+// latestSnapshots - the latest parsed snapshots of the notepads matching any of the glob patterns (all for no patterns)
+//
+// The snapshots are ordered by the notepad, the unreadable ones are skipped.
+func (app *IsStatApp) latestSnapshots(patterns []string) ([]latestSnapshot, error) {
+	var snapshots []latestSnapshot
+	for _, notepad := range app.ProgressNotepads() {
+		if len(patterns) > 0 {
+			ok, err := matchAny(patterns, notepad)
+			if err != nil {
+				return snapshots, err
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		items := app.NotepadSnapshots(notepad, "json")
+		latest := items[len(items)-1]
+		students, err := app.readStudentInfo(&latest)
+		if err != nil {
+			log.WithError(err).WithField("notepad", notepad).Error("Unable to read the parsed notepad")
+			continue
+		}
+		snapshots = append(snapshots, latestSnapshot{Name: notepad, TimeStamp: latest.TimeStamp, Students: students})
+	}
+	return snapshots, nil
+}
+
+// NotepadProgress - computes the progress of the notepad from its parsed (json) snapshots
+func (app *IsStatApp) NotepadProgress(notepad string) (NotepadProgress, error) {
+	progress := NotepadProgress{Name: notepad}
+
+	snapshots := app.NotepadSnapshots(notepad, "json")
+	if len(snapshots) == 0 {
+		return progress, fmt.Errorf("no parsed snapshots of the notepad '%s'", notepad)
+	}
+
+	var latest []core.StudentInfo
+	for i := range snapshots {
+		students, err := app.readStudentInfo(&snapshots[i])
+		if err != nil {
+			log.WithError(err).WithField("snapshot", snapshots[i].GetFullName()).Warning("Skipping the unreadable snapshot")
+			continue
+		}
+		progress.History = append(progress.History, core.ComputeNotepadSummary(notepad, snapshots[i].TimeStamp, students))
+		latest = students
+	}
+
+	if len(progress.History) == 0 {
+		return progress, fmt.Errorf("no readable snapshots of the notepad '%s'", notepad)
+	}
+
+	progress.Summary = progress.History[len(progress.History)-1]
+	progress.Scores = core.NewHistogram(core.FinalPoints(latest), scoreBins)
+	progress.Attempts = core.NewHistogram(core.Attempts(latest), scoreBins)
+	progress.Timeline = core.NewSubmissionTimeline(latest, time.Local)
+
+	if app.Config != nil {
+		deadline, ok, err := app.Config.NotepadDeadline(notepad)
+		if err != nil {
+			return progress, err
+		}
+		if ok {
+			progress.Deadline = deadline
+			lateness := core.NewLateness(latest, deadline)
+			progress.Lateness = &lateness
+		}
+	}
+
+	return progress, nil
+}
+
+// ScoreChart - SVG histogram of the final submissions' points
+func (progress *NotepadProgress) ScoreChart() string {
+	chart := charts.BarChart{Title: "Points of the final submissions"}
+	for _, bucket := range progress.Scores {
+		chart.Bars = append(chart.Bars, charts.Bar{Label: bucket.Label(), Value: float64(bucket.Count)})
+	}
+	return chart.SVG()
+}
+
+// SubmissionsChart - SVG chart of the submissions per day
+func (progress *NotepadProgress) SubmissionsChart() string {
+	series := charts.Series{Name: "submissions"}
+	for _, point := range progress.Timeline {
+		series.Points = append(series.Points, charts.TimePoint{Time: point.Day, Value: float64(point.Count)})
+	}
+	return (&charts.LineChart{Title: "Submissions per day", Series: []charts.Series{series}}).SVG()
+}
+
+// CumulativeChart - SVG chart of the cumulative submissions with the deadline
+func (progress *NotepadProgress) CumulativeChart() string {
+	series := charts.Series{Name: "submissions"}
+	for _, point := range progress.Timeline {
+		series.Points = append(series.Points, charts.TimePoint{Time: point.Day, Value: float64(point.Cumulative)})
+	}
+	return (&charts.LineChart{
+		Title:   "Cumulative submissions",
+		Series:  []charts.Series{series},
+		Markers: progress.deadlineMarkers(),
+	}).SVG()
+}
+
+// FinishedChart - SVG chart of the share of the students with the final submission over the snapshots
+func (progress *NotepadProgress) FinishedChart() string {
+	series := charts.Series{Name: "finished"}
+	for _, summary := range progress.History {
+		timestamp, err := time.ParseInLocation(core.TimestampFormat, summary.TimeStamp, time.Local)
+		if err != nil {
+			continue
+		}
+		series.Points = append(series.Points, charts.TimePoint{Time: timestamp, Value: summary.FinalShare})
+	}
+	return (&charts.LineChart{
+		Title:   "Students finished",
+		Series:  []charts.Series{series},
+		Markers: progress.deadlineMarkers(),
+		YMax:    1,
+		Percent: true,
+	}).SVG()
+}
+
+func (progress *NotepadProgress) deadlineMarkers() []charts.Marker {
+	if progress.Deadline.IsZero() {
+		return nil
+	}
+	return []charts.Marker{{Time: progress.Deadline, Label: "deadline"}}
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/pestanko/isstat/core"
+	log "github.com/sirupsen/logrus"
+)
+
+// Rekey - derives the students' pseudonyms from the new key and rewrites the parsed snapshots
+//
+// The previous pseudonyms are taken from the students register (and derived from the current key
+// in the hmac mode), so it works for the migration from the random pseudonyms as well.
+// All the matching snapshots are loaded before anything is changed.
+// When any snapshot can not be rewritten, the already rewritten ones are restored
+// and the register is not saved, so the snapshots and the register keep the previous pseudonyms.
+// The compressed snapshots are rewritten uncompressed (name.timestamp.json), the compressed files are removed.
+func (app *IsStatApp) Rekey(newKey []byte, patterns []string) ([]core.ResultItem, error) {
+	if len(newKey) < core.MinPseudonymKeyLength {
+		return nil, fmt.Errorf("new pseudonym key is too short: %d bytes, at least %d required",
+			len(newKey), core.MinPseudonymKeyLength)
+	}
+
+	type snapshot struct {
+		item     core.ResultItem
+		info     []core.StudentInfo
+		original []byte
+	}
+
+	var items []core.ResultItem
+
+	err := app.withStudentsRegister(func() error {
+		var snapshots []snapshot
+		for _, name := range app.Results.GlobAll(patterns) {
+			item := core.NewResultItemFromFullName(name)
+			if item.BaseExt() != "json" {
+				continue
+			}
+
+			info, err := app.readStudentInfo(&item)
+			if err != nil {
+				log.WithError(err).WithField("notepad", name).Error("Unable to read the parsed notepad")
+				return err
+			}
+			snapshots = append(snapshots, snapshot{item: item, info: info, original: item.Data})
+		}
+
+		mapping := app.Register.Rekey(newKey)
+		log.WithField("students", len(app.Register.Users)).WithField("snapshots", len(snapshots)).Info("Re-keying the snapshots")
+
+		for i := range snapshots {
+			snap := &snapshots[i]
+			entry := log.WithField("name", snap.item.GetFullName())
+			if missing := core.RemapStudentIDs(snap.info, mapping); missing > 0 {
+				entry.WithField("missing", missing).Warning("Students not found in the register keep their IDs")
+			}
+
+			if app.Config != nil && app.Config.DryRun {
+				items = append(items, snap.item)
+				continue
+			}
+
+			rekeyed, err := app.storeRekeyedSnapshot(snap.item, snap.info)
+			if err != nil {
+				entry.WithError(err).Error("Unable to store the re-keyed snapshot")
+				for j, stored := range snapshots[:i] {
+					app.restoreSnapshot(stored.item, items[j], stored.original)
+				}
+				items = nil
+				return fmt.Errorf("unable to store the re-keyed snapshot '%s', the snapshots are restored: %v",
+					snap.item.GetFullName(), err)
+			}
+			items = append(items, rekeyed)
+		}
+		return nil
+	}, false)
+
+	return items, err
+}
+
+// storeRekeyedSnapshot - stores the re-keyed students, the compressed snapshot is replaced by the uncompressed one
+func (app *IsStatApp) storeRekeyedSnapshot(item core.ResultItem, info []core.StudentInfo) (core.ResultItem, error) {
+	rekeyed := item
+	rekeyed.Ext = item.BaseExt()
+	if err := app.storeStudentInfo(&rekeyed, info); err != nil {
+		return item, err
+	}
+	if rekeyed.Ext != item.Ext {
+		if err := app.Results.Delete(&item); err != nil {
+			_ = app.Results.Delete(&rekeyed)
+			return item, err
+		}
+	}
+	return rekeyed, nil
+}
+
+// restoreSnapshot - stores the original content of the snapshot back, the re-keyed one is removed when renamed
+func (app *IsStatApp) restoreSnapshot(item, rekeyed core.ResultItem, original []byte) {
+	item.Data = original
+	if err := app.storeResultItem(&item); err != nil {
+		log.WithError(err).WithField("name", item.GetFullName()).Error("Unable to restore the snapshot")
+		return
+	}
+	if rekeyed.Ext != item.Ext {
+		if err := app.Results.Delete(&rekeyed); err != nil {
+			log.WithError(err).WithField("name", rekeyed.GetFullName()).Error("Unable to remove the re-keyed snapshot")
+		}
+	}
+}
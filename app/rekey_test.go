@@ -0,0 +1,116 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pestanko/isstat/core"
+)
+
+// failingStore - filesystem store failing to put the named notepad
+type failingStore struct {
+	*core.FilesystemStore
+	notepad string
+}
+
+func (store *failingStore) Put(item *core.ResultItem) error {
+	if item.Name == store.notepad {
+		return fmt.Errorf("unable to write %s", item.GetFullName())
+	}
+	return store.FilesystemStore.Put(item)
+}
+
+func TestRekey_FailedRewriteKeepsRegisterAndSnapshots(t *testing.T) {
+	// GIVEN
+	dir, err := ioutil.TempDir("", "isstat-rekey")
+	if err != nil {
+		t.Fatalf("FAIL: Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	registerFile := filepath.Join(dir, "register", "students.json")
+	register := core.NewStudentsRegister()
+	register.GetOrRegister("123456")
+	if err := os.MkdirAll(filepath.Dir(registerFile), 0700); err != nil {
+		t.Fatalf("FAIL: Unable to create the register directory: %v", err)
+	}
+	if err := register.Export(registerFile); err != nil {
+		t.Fatalf("FAIL: Unable to export the register: %v", err)
+	}
+	registerContent, _ := ioutil.ReadFile(registerFile)
+
+	store := core.NewFilesystemStore(dir)
+	students := []byte(fmt.Sprintf(`[{"uid":"%s","submissions":[]}]`, register.Users["123456"]))
+	for _, name := range []string{"hw01", "hw02"} {
+		if err := store.Put(&core.ResultItem{Name: name, TimeStamp: "2020-03-01T10-00-00", Ext: "json", Data: students}); err != nil {
+			t.Fatalf("FAIL: Unable to store the result: %v", err)
+		}
+	}
+
+	loaded := core.NewStudentsRegister()
+	application := &IsStatApp{
+		Results:  core.NewResultsWithStore(&failingStore{FilesystemStore: store, notepad: "hw02"}, false),
+		Register: &loaded,
+		Config:   &Config{Register: registerFile},
+	}
+
+	// WHEN
+	items, err := application.Rekey([]byte("0123456789abcdef0123"), []string{"*"})
+
+	// THEN
+	if err == nil || len(items) != 0 {
+		t.Errorf("FAIL: Expected the failed re-key, got %v items and error %v", len(items), err)
+	}
+	if content, _ := ioutil.ReadFile(registerFile); !bytes.Equal(content, registerContent) {
+		t.Errorf("FAIL: The register was saved after the failed re-key: %s", content)
+	}
+	if content, _ := ioutil.ReadFile(filepath.Join(dir, "hw01.2020-03-01T10-00-00.json")); !bytes.Equal(content, students) {
+		t.Errorf("FAIL: The rewritten snapshot was not restored: %s", content)
+	}
+}
+
+func TestRekey_RewritesCompressedSnapshots(t *testing.T) {
+	// GIVEN
+	dir, err := ioutil.TempDir("", "isstat-rekey")
+	if err != nil {
+		t.Fatalf("FAIL: Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	register := core.NewStudentsRegister()
+	oldID := register.GetOrRegister("123456")
+
+	compressed := gzipTestData([]byte(fmt.Sprintf(`[{"uid":"%s","submissions":[]}]`, oldID)))
+
+	application := &IsStatApp{
+		Results:  core.NewResults(dir, false),
+		Register: &register,
+		Config:   &Config{Register: filepath.Join(dir, "register", "students.json")},
+	}
+	item := core.ResultItem{Name: "hw01", TimeStamp: "2020-03-01T10-00-00", Ext: "json.gz", Data: compressed}
+	if err := application.Results.Store(&item); err != nil {
+		t.Fatalf("FAIL: Unable to store the result: %v", err)
+	}
+
+	// WHEN
+	items, err := application.Rekey([]byte("0123456789abcdef0123"), []string{"*.json*"})
+
+	// THEN
+	if err != nil || len(items) != 1 || items[0].Ext != "json" {
+		t.Fatalf("FAIL: Expected the re-keyed uncompressed snapshot, got %v and error %v", items, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "hw01.2020-03-01T10-00-00.json.gz")); !os.IsNotExist(err) {
+		t.Errorf("FAIL: The compressed snapshot with the old pseudonyms was kept: %v", err)
+	}
+	students, err := application.readStudentInfo(&items[0])
+	if err != nil {
+		t.Fatalf("FAIL: Unable to read the re-keyed snapshot: %v", err)
+	}
+	if len(students) != 1 || students[0].ID == oldID || students[0].ID != register.Users["123456"] {
+		t.Errorf("FAIL: Expected the new pseudonym %v, got %v", register.Users["123456"], students)
+	}
+}
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Report formats
+const (
+	ReportHTML     = "html"
+	ReportMarkdown = "markdown"
+)
+
+// reportIndexName - name of the overview page of the static report
+const reportIndexName = "index.html"
+
+// reportPagePrefix - prefix of the notepad pages, so no notepad (for example "index") overwrites the overview
+const reportPagePrefix = "notepad-"
+
+// Report - progress of the notepads for the static report
+type Report struct {
+	Generated time.Time
+	Notepads  []*NotepadProgress
+}
+
+// BuildReport - computes the progress of the parsed notepads matching any of the glob patterns
+//
+// No patterns means all the parsed notepads.
+func (app *IsStatApp) BuildReport(patterns []string) (Report, error) {
+	report := Report{Generated: time.Now()}
+
+	for _, notepad := range app.ProgressNotepads() {
+		if len(patterns) > 0 {
+			ok, err := matchAny(patterns, notepad)
+			if err != nil {
+				return report, err
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		progress, err := app.NotepadProgress(notepad)
+		if err != nil {
+			log.WithError(err).WithField("notepad", notepad).Warning("Skipping the notepad in the report")
+			continue
+		}
+		report.Notepads = append(report.Notepads, &progress)
+	}
+
+	return report, nil
+}
+
+// WriteHTML - writes the standalone static report to the directory
+//
+// The overview (index.html) and one page per notepad (notepad-NAME.html) are written, the styles and the charts
+// are inlined, so the report can be archived and viewed offline.
+// It returns the written files.
+func (report *Report) WriteHTML(dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	var files []string
+	write := func(name string, page []byte) error {
+		file := filepath.Join(dir, name)
+		if err := writeReportFile(file, page); err != nil {
+			return err
+		}
+		files = append(files, file)
+		return nil
+	}
+
+	for _, progress := range report.Notepads {
+		page, err := executeDashboard(dashboardNotepadTemplate, map[string]interface{}{
+			"Title":     progress.Name,
+			"Progress":  progress,
+			"Static":    true,
+			"Generated": report.Generated,
+		})
+		if err != nil {
+			return files, fmt.Errorf("unable to render the notepad '%s': %v", progress.Name, err)
+		}
+		if err := write(reportPageName(progress.Name), page); err != nil {
+			return files, err
+		}
+	}
+
+	index, err := executeDashboard(dashboardIndexTemplate, map[string]interface{}{
+		"Title":     "Notepads",
+		"Notepads":  report.Notepads,
+		"Static":    true,
+		"Generated": report.Generated,
+	})
+	if err != nil {
+		return files, fmt.Errorf("unable to render the overview: %v", err)
+	}
+	return files, write(reportIndexName, index)
+}
+
+// reportPageName - name of the notepad page in the static report
+func reportPageName(notepad string) string {
+	return reportPagePrefix + notepad + ".html"
+}
+
+func writeReportFile(file string, content []byte) error {
+	log.WithField("file", file).Info("Writing the report")
+	return ioutil.WriteFile(file, content, 0644)
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pestanko/isstat/core"
+)
+
+// WriteMarkdown - writes the report as the GitHub/GitLab flavoured Markdown
+//
+// The overview table of the notepads is followed by the section per notepad
+// with the score summary, the attempts distribution and the lateness (when the deadline is configured).
+func (report *Report) WriteMarkdown(w io.Writer) error {
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintf(out, "# Notepads report\n\nGenerated %s\n\n", report.Generated.Format("2006-01-02 15:04"))
+
+	if len(report.Notepads) == 0 {
+		fmt.Fprintln(out, "No parsed notepads.")
+		return out.Flush()
+	}
+
+	fmt.Fprintln(out, "| Notepad | Snapshot | Students | Submitted | Submissions | Finished | Mean | Median | Std dev | Min | Max | Late | Deadline |")
+	fmt.Fprintln(out, "|---|---|---:|---:|---:|---:|---:|---:|---:|---:|---:|---:|---|")
+	for _, progress := range report.Notepads {
+		summary := &progress.Summary
+		fmt.Fprintf(out, "| %s | %s | %d | %d | %d | %.0f %% | %.2f | %.2f | %.2f | %.2f | %.2f | %s | %s |\n",
+			markdownEscape(progress.Name), summary.TimeStamp, summary.Students, summary.Submitted, summary.Submissions,
+			summary.FinalShare*100, summary.Points.Mean, summary.Points.Median, summary.Points.StdDev,
+			summary.Points.Min, summary.Points.Max, markdownLateShare(progress.Lateness), markdownDeadline(progress))
+	}
+
+	for _, progress := range report.Notepads {
+		fmt.Fprintf(out, "\n## %s\n\n", markdownEscape(progress.Name))
+		writeMarkdownSummary(out, &progress.Summary)
+		writeMarkdownHistogram(out, "Attempts", "Students", progress.Attempts)
+		writeMarkdownHistogram(out, "Points", "Students", progress.Scores)
+		writeMarkdownLateness(out, progress.Lateness)
+	}
+
+	return out.Flush()
+}
+
+func writeMarkdownSummary(out io.Writer, summary *core.NotepadSummary) {
+	fmt.Fprintln(out, "| | Mean | Median | Std dev | Min | Max |")
+	fmt.Fprintln(out, "|---|---:|---:|---:|---:|---:|")
+	for _, row := range []struct {
+		name  string
+		value *core.ValueSummary
+	}{{"Points", &summary.Points}, {"Bonus", &summary.Bonus}} {
+		fmt.Fprintf(out, "| %s | %.2f | %.2f | %.2f | %.2f | %.2f |\n",
+			row.name, row.value.Mean, row.value.Median, row.value.StdDev, row.value.Min, row.value.Max)
+	}
+	fmt.Fprintln(out)
+}
+
+func writeMarkdownHistogram(out io.Writer, name, count string, buckets []core.HistogramBucket) {
+	if len(buckets) == 0 {
+		return
+	}
+
+	fmt.Fprintf(out, "| %s | %s |\n|---:|---:|\n", name, count)
+	for _, bucket := range buckets {
+		fmt.Fprintf(out, "| %s | %d |\n", bucket.Label(), bucket.Count)
+	}
+	fmt.Fprintln(out)
+}
+
+func writeMarkdownLateness(out io.Writer, lateness *core.Lateness) {
+	if lateness == nil {
+		return
+	}
+
+	fmt.Fprintf(out, "**Lateness** (deadline %s): %d of %d submissions late (%.0f %%), "+
+		"%d students submitted late, %d final submissions late.\n",
+		lateness.Deadline.Format(DeadlineLayout), lateness.Late, lateness.Submissions, lateness.LateShare()*100,
+		lateness.LateStudents, lateness.FinalLate)
+}
+
+func markdownLateShare(lateness *core.Lateness) string {
+	if lateness == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f %%", lateness.LateShare()*100)
+}
+
+func markdownDeadline(progress *NotepadProgress) string {
+	if progress.Deadline.IsZero() {
+		return "-"
+	}
+	return progress.Deadline.Format(DeadlineLayout)
+}
+
+// markdownEscape - escapes the characters breaking the tables and the formatting
+func markdownEscape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`").Replace(text)
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pestanko/isstat/core"
+)
+
+func TestReport_WriteHTML(t *testing.T) {
+	// GIVEN
+	var items []core.ResultItem
+	for _, name := range []string{"hw01", "hw02", "exam", "index"} {
+		item := core.NewResultItem(name, "2020-03-01T10-00-00", "json")
+		item.Data = testStudents(`{"index":0,"points":5,"final":true}`)
+		items = append(items, item)
+	}
+	application, cleanup := newResultsTestApp(t, items...)
+	defer cleanup()
+	dir := application.Results.Location()
+
+	// WHEN
+	report, err := application.BuildReport([]string{"hw*", "index"})
+	if err != nil {
+		t.Fatalf("FAIL: Unable to build the report: %v", err)
+	}
+	files, err := report.WriteHTML(filepath.Join(dir, "report"))
+
+	// THEN
+	if err != nil {
+		t.Fatalf("FAIL: Unable to write the report: %v", err)
+	}
+	if len(files) != 4 {
+		t.Fatalf("FAIL: Expected 3 notepad pages and the overview, got %v", files)
+	}
+
+	index, err := ioutil.ReadFile(filepath.Join(dir, "report", "index.html"))
+	if err != nil {
+		t.Fatalf("FAIL: Unable to read the overview: %v", err)
+	}
+	if !strings.Contains(string(index), `href="notepad-hw01.html"`) || !strings.Contains(string(index), `href="notepad-index.html"`) ||
+		strings.Contains(string(index), "exam") {
+		t.Errorf("FAIL: Overview should link the matching notepads relatively: %s", index)
+	}
+
+	page, err := ioutil.ReadFile(filepath.Join(dir, "report", "notepad-hw02.html"))
+	if err != nil {
+		t.Fatalf("FAIL: Unable to read the notepad page: %v", err)
+	}
+	if strings.Contains(string(page), "/assets/") || !strings.Contains(string(page), "<style>") || strings.Count(string(page), "<svg") != 4 {
+		t.Errorf("FAIL: Notepad page should be standalone with the inline charts: %s", page)
+	}
+}
+
+func TestReport_WriteMarkdown(t *testing.T) {
+	// GIVEN
+	deadline := time.Date(2020, 3, 8, 23, 59, 0, 0, time.UTC)
+	lateness := core.NewLateness([]core.StudentInfo{
+		{Submissions: []core.Submission{{DateTime: deadline.Add(time.Hour), Final: true}}},
+	}, deadline)
+	report := Report{
+		Generated: deadline,
+		Notepads: []*NotepadProgress{{
+			Name:     "hw|01",
+			Summary:  core.NotepadSummary{Name: "hw|01", Students: 1, Submitted: 1, Submissions: 1, FinalShare: 1},
+			Deadline: deadline,
+			Attempts: []core.HistogramBucket{{From: 0, To: 1}, {From: 1, To: 2, Count: 1}},
+			Lateness: &lateness,
+		}},
+	}
+
+	// WHEN
+	var out bytes.Buffer
+	err := report.WriteMarkdown(&out)
+
+	// THEN
+	if err != nil {
+		t.Fatalf("FAIL: Unable to write the report: %v", err)
+	}
+	markdown := out.String()
+	for _, expected := range []string{
+		`| hw\|01 | `,
+		"| 100 % | 2020-03-08 23:59 |",
+		"## hw\\|01",
+		"| Attempts | Students |\n|---:|---:|\n| 0 | 0 |\n| 1 | 1 |",
+		"1 of 1 submissions late (100 %), 1 students submitted late, 1 final submissions late",
+	} {
+		if !strings.Contains(markdown, expected) {
+			t.Errorf("FAIL: Report should contain '%s':\n%s", expected, markdown)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// dueTolerance - the notepad is due slightly before its interval elapses, so the tick jitter does not skip it
+const dueTolerance = 30 * time.Second
+
+// FetchTier - fetch interval used while the distance to the deadline is within the window
+type FetchTier struct {
+	Within   time.Duration
+	Interval time.Duration
+}
+
+// AdaptivePolicy - fetches the notepads more often close to their deadlines
+//
+// The Before tiers are used before the deadline, the After tiers after it,
+// the first tier whose window contains the distance to the deadline wins.
+// The Default interval is used outside of all the windows and for the notepads without the deadline
+// (0 means the notepad is fetched on every tick).
+type AdaptivePolicy struct {
+	Deadlines map[string]time.Time
+	Before    []FetchTier
+	After     []FetchTier
+	Default   time.Duration
+}
+
+// DefaultBeforeTiers - fetch intervals before the deadline
+var DefaultBeforeTiers = []FetchTier{
+	{Within: time.Hour, Interval: time.Minute},
+	{Within: 6 * time.Hour, Interval: 5 * time.Minute},
+	{Within: 24 * time.Hour, Interval: 15 * time.Minute},
+	{Within: 7 * 24 * time.Hour, Interval: 2 * time.Hour},
+}
+
+// DefaultAdaptiveInterval - fetch interval outside of the deadline windows when none is configured
+const DefaultAdaptiveInterval = 24 * time.Hour
+
+// DefaultAfterTiers - fetch intervals after the deadline
+var DefaultAfterTiers = []FetchTier{
+	{Within: time.Hour, Interval: 5 * time.Minute},
+	{Within: 24 * time.Hour, Interval: time.Hour},
+}
+
+// NewAdaptivePolicy - creates the policy with the default tiers, the deadlines are matched case-insensitively
+func NewAdaptivePolicy(deadlines map[string]time.Time, defaultInterval time.Duration) *AdaptivePolicy {
+	normalized := make(map[string]time.Time)
+	for notepad, deadline := range deadlines {
+		normalized[strings.ToLower(notepad)] = deadline
+	}
+
+	return &AdaptivePolicy{
+		Deadlines: normalized,
+		Before:    DefaultBeforeTiers,
+		After:     DefaultAfterTiers,
+		Default:   defaultInterval,
+	}
+}
+
+// Interval - fetch interval of the notepad at the time
+func (policy *AdaptivePolicy) Interval(notepad string, now time.Time) time.Duration {
+	deadline, ok := policy.Deadlines[strings.ToLower(notepad)]
+	if !ok {
+		return policy.Default
+	}
+
+	tiers := policy.Before
+	distance := deadline.Sub(now)
+	if distance < 0 {
+		tiers = policy.After
+		distance = -distance
+	}
+
+	for _, tier := range tiers {
+		if distance <= tier.Within {
+			return tier.Interval
+		}
+	}
+	return policy.Default
+}
+
+// Due - whether the notepad last fetched at the time should be fetched now
+func (policy *AdaptivePolicy) Due(notepad string, last, now time.Time) bool {
+	if last.IsZero() {
+		return true
+	}
+	return now.Sub(last)+dueTolerance >= policy.Interval(notepad, now)
+}
+
+// ParseFetchTiers - parses the tiers in the "within=interval" format, for example "1h=1m"
+func ParseFetchTiers(values []string) ([]FetchTier, error) {
+	var tiers []FetchTier
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid fetch tier '%s', expected within=interval", value)
+		}
+
+		within, err := time.ParseDuration(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid fetch tier '%s': %v", value, err)
+		}
+
+		interval, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid fetch tier '%s': %v", value, err)
+		}
+
+		tiers = append(tiers, FetchTier{Within: within, Interval: interval})
+	}
+
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].Within < tiers[j].Within
+	})
+	return tiers, nil
+}
+
+// NewAdaptivePolicyFromConfig - creates the policy from the notepads' deadlines and the serve config
+func NewAdaptivePolicyFromConfig(config *Config) (*AdaptivePolicy, error) {
+	deadlines, err := config.NotepadDeadlines()
+	if err != nil {
+		return nil, err
+	}
+
+	defaultInterval := DefaultAdaptiveInterval
+	if config.Serve.DefaultInterval != "" {
+		if defaultInterval, err = time.ParseDuration(config.Serve.DefaultInterval); err != nil {
+			return nil, fmt.Errorf("invalid default interval '%s': %v", config.Serve.DefaultInterval, err)
+		}
+	}
+
+	policy := NewAdaptivePolicy(deadlines, defaultInterval)
+
+	if len(config.Serve.Before) > 0 {
+		if policy.Before, err = ParseFetchTiers(config.Serve.Before); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(config.Serve.After) > 0 {
+		if policy.After, err = ParseFetchTiers(config.Serve.After); err != nil {
+			return nil, err
+		}
+	}
+
+	return policy, nil
+}
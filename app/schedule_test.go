@@ -0,0 +1,134 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdaptivePolicy_Interval(t *testing.T) {
+	// GIVEN
+	deadline := time.Date(2020, 3, 10, 23, 59, 0, 0, time.UTC)
+	policy := NewAdaptivePolicy(map[string]time.Time{"HW01": deadline}, 6*time.Hour)
+
+	cases := []struct {
+		notepad  string
+		now      time.Time
+		expected time.Duration
+	}{
+		{"hw01", deadline.Add(-30 * time.Minute), time.Minute},
+		{"hw01", deadline.Add(-3 * time.Hour), 5 * time.Minute},
+		{"hw01", deadline.Add(-12 * time.Hour), 15 * time.Minute},
+		{"hw01", deadline.Add(-72 * time.Hour), 2 * time.Hour},
+		{"hw01", deadline.Add(-30 * 24 * time.Hour), 6 * time.Hour},
+		{"hw01", deadline.Add(30 * time.Minute), 5 * time.Minute},
+		{"hw01", deadline.Add(12 * time.Hour), time.Hour},
+		{"hw01", deadline.Add(48 * time.Hour), 6 * time.Hour},
+		{"hw02", deadline, 6 * time.Hour},
+	}
+
+	for _, c := range cases {
+		// WHEN
+		interval := policy.Interval(c.notepad, c.now)
+
+		// THEN
+		if interval != c.expected {
+			t.Errorf("FAIL: Interval of %s at %v is %v, expected %v", c.notepad, c.now, interval, c.expected)
+		}
+	}
+}
+
+func TestAdaptivePolicy_Due(t *testing.T) {
+	// GIVEN
+	deadline := time.Date(2020, 3, 10, 23, 59, 0, 0, time.UTC)
+	policy := NewAdaptivePolicy(map[string]time.Time{"hw01": deadline}, time.Hour)
+	now := deadline.Add(-3 * time.Hour)
+
+	// THEN
+	if !policy.Due("hw01", time.Time{}, now) {
+		t.Errorf("FAIL: Never fetched notepad should be due")
+	}
+	if policy.Due("hw01", now.Add(-2*time.Minute), now) {
+		t.Errorf("FAIL: Notepad fetched 2 minutes ago should not be due (interval 5m)")
+	}
+	if !policy.Due("hw01", now.Add(-5*time.Minute+10*time.Second), now) {
+		t.Errorf("FAIL: Notepad should be due within the tolerance")
+	}
+	if policy.Due("hw02", now.Add(-30*time.Minute), now) {
+		t.Errorf("FAIL: Notepad without the deadline should use the default interval")
+	}
+}
+
+func TestNewAdaptivePolicyFromConfig_DefaultInterval(t *testing.T) {
+	// WHEN
+	unconfigured, err := NewAdaptivePolicyFromConfig(&Config{})
+	if err != nil {
+		t.Fatalf("FAIL: Unable to create the policy: %v", err)
+	}
+	configured, err := NewAdaptivePolicyFromConfig(&Config{Serve: ServeConfig{DefaultInterval: "6h"}})
+	if err != nil {
+		t.Fatalf("FAIL: Unable to create the policy: %v", err)
+	}
+
+	// THEN
+	if unconfigured.Default != DefaultAdaptiveInterval {
+		t.Errorf("FAIL: Unconfigured default interval is %v, expected %v", unconfigured.Default, DefaultAdaptiveInterval)
+	}
+	if configured.Default != 6*time.Hour {
+		t.Errorf("FAIL: Configured default interval is %v, expected 6h", configured.Default)
+	}
+	if now := time.Now(); unconfigured.Due("hw01", now.Add(-time.Hour), now) {
+		t.Errorf("FAIL: Notepad without the deadline fetched an hour ago should not be due")
+	}
+}
+
+func TestParseFetchTiers(t *testing.T) {
+	// WHEN
+	tiers, err := ParseFetchTiers([]string{"24h=30m", "1h=1m"})
+
+	// THEN
+	if err != nil {
+		t.Fatalf("FAIL: Unable to parse the tiers: %v", err)
+	}
+	if len(tiers) != 2 || tiers[0] != (FetchTier{Within: time.Hour, Interval: time.Minute}) || tiers[1].Within != 24*time.Hour {
+		t.Errorf("FAIL: Unexpected tiers %v", tiers)
+	}
+
+	if _, err := ParseFetchTiers([]string{"1h"}); err == nil {
+		t.Errorf("FAIL: Tier without the interval should be rejected")
+	}
+}
+
+func TestDaemon_SyncsOnlyDueNotepads(t *testing.T) {
+	// GIVEN
+	var synced [][]string
+	daemon, cleanup := newTestDaemon(t, func(notepads []string) ([]SyncResult, error) {
+		synced = append(synced, notepads)
+		var results []SyncResult
+		for _, notepad := range notepads {
+			results = append(results, SyncResult{Notepad: notepad, Fetch: StepOK})
+		}
+		return results, nil
+	})
+	defer cleanup()
+
+	daemon.Policy = NewAdaptivePolicy(map[string]time.Time{"hw01": time.Now().Add(30 * time.Minute)}, time.Hour)
+
+	// WHEN
+	daemon.Trigger()
+	daemon.Wait()
+	daemon.Trigger()
+	daemon.Wait()
+
+	// THEN
+	if len(synced) != 1 || len(synced[0]) != 2 {
+		t.Fatalf("FAIL: Only the first run should sync both notepads, synced %v", synced)
+	}
+
+	records, err := daemon.History.Load(0)
+	if err != nil {
+		t.Fatalf("FAIL: Unable to load the history: %v", err)
+	}
+	if len(records) != 1 {
+		t.Errorf("FAIL: Run without the due notepads should not be recorded, got %d records", len(records))
+	}
+}
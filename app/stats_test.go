@@ -0,0 +1,72 @@
+package app
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/pestanko/isstat/core"
+)
+
+// testStudentID - pseudonym of the only student of the testStudents snapshots
+const testStudentID = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+
+// testStudents - parsed snapshot content of one student with the submissions (JSON objects separated by commas)
+func testStudents(submissions string) []byte {
+	return []byte(`[{"uid":"` + testStudentID + `","submissions":[` + submissions + `]}]`)
+}
+
+// gzipTestData - the data compressed by the gzip
+func gzipTestData(data []byte) []byte {
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, _ = writer.Write(data)
+	_ = writer.Close()
+	return compressed.Bytes()
+}
+
+// newResultsTestApp - application with the results in a temporary directory holding the items
+func newResultsTestApp(t *testing.T, items ...core.ResultItem) (*IsStatApp, func()) {
+	dir, err := ioutil.TempDir("", "isstat-app")
+	if err != nil {
+		t.Fatalf("FAIL: Unable to create temp dir: %v", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+
+	application := &IsStatApp{Results: core.NewResults(dir, false)}
+	for _, item := range items {
+		item := item
+		if err := application.Results.Store(&item); err != nil {
+			cleanup()
+			t.Fatalf("FAIL: Unable to store the result: %v", err)
+		}
+	}
+	return application, cleanup
+}
+
+func TestParsedSnapshots_Compressed(t *testing.T) {
+	// GIVEN
+	application, cleanup := newResultsTestApp(t,
+		core.ResultItem{Name: "hw01", TimeStamp: "2020-03-02T10-00-00", Ext: "json", Data: []byte("[]")},
+		core.ResultItem{Name: "hw01", TimeStamp: "2020-03-01T10-00-00", Ext: "json.gz",
+			Data: gzipTestData(testStudents(`{"index":0,"points":5,"final":true}`))},
+	)
+	defer cleanup()
+
+	// WHEN
+	snapshots, err := application.ParsedSnapshots([]string{"hw01.*"})
+
+	// THEN
+	if err != nil || len(snapshots) != 2 {
+		t.Fatalf("FAIL: Expected 2 snapshots, got %+v (%v)", snapshots, err)
+	}
+	first := snapshots[0]
+	if first.Item.Ext != "json.gz" || len(first.Students) != 1 || first.Summary.Students != 1 || first.Summary.Points.Mean != 5 {
+		t.Errorf("FAIL: Unexpected compressed snapshot: %+v", first)
+	}
+	if snapshots[1].Item.TimeStamp != "2020-03-02T10-00-00" || len(snapshots[1].Students) != 0 {
+		t.Errorf("FAIL: Unexpected snapshot: %+v", snapshots[1])
+	}
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/pestanko/isstat/core"
+	log "github.com/sirupsen/logrus"
+)
+
+// Sync step statuses
+const (
+	StepOK      = "ok"
+	StepSkipped = "skipped"
+	StepFailed  = "failed"
+)
+
+// SyncResult - result of the sync pipeline for one notepad
+type SyncResult struct {
+	Notepad   string               `json:"notepad"`
+	TimeStamp string               `json:"timestamp"`
+	Fetch     string               `json:"fetch"`
+	Parse     string               `json:"parse"`
+	CSV       string               `json:"csv"`
+	Stats     string               `json:"stats"`
+	Error     string               `json:"error,omitempty"`
+	Summary   *core.NotepadSummary `json:"summary,omitempty"`
+}
+
+// Failed - whether any of the steps failed
+func (result *SyncResult) Failed() bool {
+	return result.Error != ""
+}
+
+func (result *SyncResult) fail(step *string, err error) {
+	*step = StepFailed
+	if result.Error == "" {
+		result.Error = err.Error()
+	}
+}
+
+// Sync - runs the fetch, parse, csv and stats pipeline for the notepads on one snapshot timestamp
+//
+// The pipeline continues past the failures of one notepad,
+// the steps following the failed one are skipped for that notepad. Only the fetch runs in the dry run.
+func (app *IsStatApp) Sync(notepads []string) ([]SyncResult, error) {
+	timestamp := core.GetCurrentTimestamp()
+	return app.SyncWithTimestamp(notepads, timestamp)
+}
+
+// SyncWithTimestamp - runs the sync pipeline on the provided snapshot timestamp
+//
+// No notepads to sync is reported as the error, so the empty match does not pass unnoticed.
+func (app *IsStatApp) SyncWithTimestamp(notepads []string, timestamp string) ([]SyncResult, error) {
+	if len(notepads) == 0 {
+		return nil, fmt.Errorf("no notepads to sync, provide the notepads or use --all or --match")
+	}
+
+	results := make([]SyncResult, len(notepads))
+	for i, notepad := range notepads {
+		results[i] = SyncResult{
+			Notepad:   notepad,
+			TimeStamp: timestamp,
+			Fetch:     StepSkipped,
+			Parse:     StepSkipped,
+			CSV:       StepSkipped,
+			Stats:     StepSkipped,
+		}
+	}
+
+	log.WithField("notepads", notepads).WithField("timestamp", timestamp).Info("Sync notepads")
+	items, errs := app.FetchEach(notepads, timestamp)
+
+	// the client downloads nothing in the dry run (see core.CourseClient.Fetch), so there is nothing to parse and convert
+	dryRun := app.Config != nil && app.Config.DryRun
+	err := app.WithStudentsRegister(func() error {
+		for i := range results {
+			result := &results[i]
+			if errs[i] != nil {
+				result.fail(&result.Fetch, errs[i])
+				continue
+			}
+			result.Fetch = StepOK
+			if dryRun {
+				continue
+			}
+
+			info, err := app.ParseOne(items[i].GetFullName())
+			if err != nil {
+				result.fail(&result.Parse, err)
+				continue
+			}
+			result.Parse = StepOK
+
+			summary := core.ComputeNotepadSummary(result.Notepad, timestamp, info)
+			result.Summary = &summary
+			result.Stats = StepOK
+		}
+		return nil
+	})
+	if err != nil {
+		return results, err
+	}
+
+	for i := range results {
+		result := &results[i]
+		if result.Parse != StepOK {
+			continue
+		}
+
+		jsonItem := core.NewResultItem(result.Notepad, timestamp, "json")
+		if _, err := app.ConvertToCSVOne(jsonItem.GetFullName()); err != nil {
+			result.fail(&result.CSV, err)
+			continue
+		}
+		result.CSV = StepOK
+	}
+
+	failed := 0
+	for i := range results {
+		if results[i].Failed() {
+			log.WithField("notepad", results[i].Notepad).WithField("error", results[i].Error).Warning("Sync failed")
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return results, fmt.Errorf("sync failed for %d of %d notepads", failed, len(results))
+	}
+	return results, nil
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pestanko/isstat/core"
+	"github.com/pestanko/isstat/parsers"
+)
+
+const syncTestTimestamp = "2020-03-01T10-00-00"
+
+// newSyncTestApp - application fetching from the IS server mock, the notepads in missing are not found
+func newSyncTestApp(t *testing.T, missing ...string) (*IsStatApp, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, notepad := range missing {
+			if strings.Contains(r.URL.RawQuery, "zkratka="+notepad) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+		}
+		_, _ = w.Write([]byte(parseTestNotepad))
+	}))
+
+	dir, err := ioutil.TempDir("", "isstat-sync")
+	if err != nil {
+		t.Fatalf("FAIL: Unable to create temp dir: %v", err)
+	}
+
+	client := core.NewCourseClient(server.URL, "token", 1433, "PB071")
+	client.RateLimit = 0
+	register := core.NewStudentsRegister()
+	application := &IsStatApp{
+		Client:   client,
+		Parser:   &parsers.BasicParser{StudentsRegister: &register, NotepadContentParser: &parsers.KontrFunctionalityParser{}},
+		Results:  core.NewResults(dir, false),
+		Register: &register,
+		Config:   &Config{},
+	}
+	return application, func() {
+		server.Close()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestSync_AllSteps(t *testing.T) {
+	// GIVEN
+	application, cleanup := newSyncTestApp(t)
+	defer cleanup()
+
+	// WHEN
+	results, err := application.SyncWithTimestamp([]string{"hw01", "hw02"}, syncTestTimestamp)
+
+	// THEN
+	if err != nil {
+		t.Fatalf("FAIL: Found error: %v", err)
+	}
+	for _, result := range results {
+		if result.Failed() || result.Fetch != StepOK || result.Parse != StepOK || result.CSV != StepOK || result.Stats != StepOK {
+			t.Errorf("FAIL: Unexpected result: %+v", result)
+		}
+		if result.Summary == nil || result.Summary.Students != 1 {
+			t.Errorf("FAIL: Unexpected summary: %+v", result.Summary)
+		}
+		csv := core.NewResultItem(result.Notepad, syncTestTimestamp, "csv")
+		if _, err := application.Results.GetContent(&csv); err != nil {
+			t.Errorf("FAIL: CSV of %s not stored: %v", result.Notepad, err)
+		}
+	}
+}
+
+func TestSync_PartialFailure(t *testing.T) {
+	// GIVEN
+	application, cleanup := newSyncTestApp(t, "hw02")
+	defer cleanup()
+
+	// WHEN
+	results, err := application.SyncWithTimestamp([]string{"hw01", "hw02"}, syncTestTimestamp)
+
+	// THEN
+	if err == nil || len(results) != 2 {
+		t.Fatalf("FAIL: Expected the sync error, got %v and %d results", err, len(results))
+	}
+	if results[0].Failed() || results[0].CSV != StepOK {
+		t.Errorf("FAIL: The first notepad should succeed: %+v", results[0])
+	}
+	failed := results[1]
+	if !failed.Failed() || failed.Fetch != StepFailed || failed.Parse != StepSkipped || failed.CSV != StepSkipped {
+		t.Errorf("FAIL: The second notepad should fail the fetch and skip the rest: %+v", failed)
+	}
+}
+
+func TestSync_NoNotepads(t *testing.T) {
+	// GIVEN
+	application, cleanup := newSyncTestApp(t)
+	defer cleanup()
+
+	// WHEN
+	results, err := application.SyncWithTimestamp(nil, syncTestTimestamp)
+
+	// THEN
+	if err == nil || len(results) != 0 {
+		t.Errorf("FAIL: Expected the error without the notepads, got %v and %d results", err, len(results))
+	}
+}
+
+func TestSync_DryRunSkipsParseAndCSV(t *testing.T) {
+	// GIVEN
+	application, cleanup := newSyncTestApp(t)
+	defer cleanup()
+	application.Config.DryRun = true
+	application.Client.DryRun = true
+
+	// WHEN
+	results, err := application.SyncWithTimestamp([]string{"hw01"}, syncTestTimestamp)
+
+	// THEN
+	if err != nil || len(results) != 1 {
+		t.Fatalf("FAIL: Expected the dry run to succeed, got %v and %d results", err, len(results))
+	}
+	if result := results[0]; result.Failed() || result.Fetch != StepOK || result.Parse != StepSkipped || result.CSV != StepSkipped {
+		t.Errorf("FAIL: The dry run should skip the parse and the CSV: %+v", result)
+	}
+	csv := core.NewResultItem("hw01", syncTestTimestamp, "csv")
+	if _, err := application.Results.GetContent(&csv); err == nil {
+		t.Errorf("FAIL: CSV should not be stored in the dry run")
+	}
+}
@@ -0,0 +1,42 @@
+package app
+
+import (
+	"time"
+
+	"github.com/pestanko/isstat/core"
+	log "github.com/sirupsen/logrus"
+)
+
+// Timing - analyses the submission times of the latest parsed snapshots against the configured deadlines
+//
+// Only the parsed notepads matching any of the glob patterns (all for no patterns)
+// with the configured deadline (see Config.NotepadDeadline) are analysed.
+func (app *IsStatApp) Timing(patterns []string) ([]core.TimingAnalysis, error) {
+	var analyses []core.TimingAnalysis
+
+	snapshots, err := app.latestSnapshots(patterns)
+	if err != nil {
+		return analyses, err
+	}
+
+	for _, snapshot := range snapshots {
+		entry := log.WithField("notepad", snapshot.Name)
+		if app.Config == nil {
+			entry.Warning("No deadline configured, skipping")
+			continue
+		}
+
+		deadline, ok, err := app.Config.NotepadDeadline(snapshot.Name)
+		if err != nil {
+			return analyses, err
+		}
+		if !ok {
+			entry.Warning("No deadline configured, skipping")
+			continue
+		}
+
+		analyses = append(analyses, core.NewTimingAnalysis(snapshot.Name, snapshot.TimeStamp, snapshot.Students, deadline, time.Local))
+	}
+
+	return analyses, nil
+}
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/pestanko/isstat/core"
+)
+
+func TestTiming_OnlyNotepadsWithDeadline(t *testing.T) {
+	// GIVEN
+	students := testStudents(`{"index":0,"datetime":"2020-03-09T00:59:00Z","final":true}`)
+	application, cleanup := newResultsTestApp(t,
+		core.ResultItem{Name: "hw01", TimeStamp: "2020-03-01T10-00-00", Ext: "json", Data: []byte("[]")},
+		core.ResultItem{Name: "hw01", TimeStamp: "2020-03-09T10-00-00", Ext: "json", Data: students},
+		core.ResultItem{Name: "hw02", TimeStamp: "2020-03-09T10-00-00", Ext: "json", Data: students},
+	)
+	defer cleanup()
+	application.Config = &Config{Notepads: map[string]NotepadConfig{
+		"HW01": {Deadline: "2020-03-08T23:59:00Z"},
+	}}
+
+	// WHEN
+	analyses, err := application.Timing(nil)
+
+	// THEN
+	if err != nil {
+		t.Fatalf("FAIL: Unable to analyse: %v", err)
+	}
+	if len(analyses) != 1 || analyses[0].Notepad != "hw01" || analyses[0].TimeStamp != "2020-03-09T10-00-00" {
+		t.Fatalf("FAIL: Expected the latest snapshot of the notepad with the deadline, got %+v", analyses)
+	}
+	if analyses[0].Lateness.Late != 1 || analyses[0].FinalHours.Max != -1 {
+		t.Errorf("FAIL: Unexpected analysis %+v", analyses[0])
+	}
+}
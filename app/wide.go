@@ -0,0 +1,44 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/pestanko/isstat/core"
+)
+
+// WideTable - pivots the latest parsed snapshots of the notepads matching any of the glob patterns (all for no patterns)
+//
+// The pivot is core.PivotAttempt or core.PivotNotepad, the points (final or best) apply to the notepad pivot only.
+func (app *IsStatApp) WideTable(patterns []string, pivot, points string) (core.WideTable, error) {
+	if pivot != core.PivotAttempt && pivot != core.PivotNotepad {
+		return core.WideTable{}, fmt.Errorf("unknown pivot '%s', expected attempt or notepad", pivot)
+	}
+
+	snapshots, err := app.latestSnapshots(patterns)
+	if err != nil {
+		return core.WideTable{}, err
+	}
+
+	notepads := make(map[string][]core.StudentInfo)
+	for _, snapshot := range snapshots {
+		notepads[snapshot.Name] = snapshot.Students
+	}
+
+	if len(notepads) == 0 {
+		return core.WideTable{}, fmt.Errorf("no parsed notepads matching %v", patterns)
+	}
+
+	if pivot == core.PivotAttempt {
+		return core.NewAttemptsTable(notepads), nil
+	}
+	return core.NewNotepadsTable(notepads, points)
+}
+
+// MarshalWide - marshals the wide table to the CSV of the globally configured format
+func (app *IsStatApp) MarshalWide(table *core.WideTable) ([]byte, error) {
+	var format core.CSVFormat
+	if app.Config != nil {
+		format = app.Config.CSV
+	}
+	return format.MarshalWide(table)
+}
@@ -0,0 +1,299 @@
+package charts
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+	"time"
+)
+
+// Default chart size in pixels
+const (
+	DefaultWidth  = 640
+	DefaultHeight = 320
+)
+
+// Palette - colors of the series
+var Palette = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b"}
+
+// margins around the plot area (for the title and the axis labels)
+const (
+	marginTop    = 32
+	marginRight  = 16
+	marginBottom = 40
+	marginLeft   = 48
+)
+
+// Bar - one bar of the bar chart
+type Bar struct {
+	Label string
+	Value float64
+}
+
+// BarChart - vertical bar chart
+type BarChart struct {
+	Title  string
+	Bars   []Bar
+	Width  int
+	Height int
+	Color  string
+}
+
+// TimePoint - one value of the time series
+type TimePoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series - named time series of the line chart
+type Series struct {
+	Name   string
+	Points []TimePoint
+	Color  string
+}
+
+// Marker - vertical line marking the time (for example the deadline)
+type Marker struct {
+	Time  time.Time
+	Label string
+}
+
+// LineChart - time series chart
+type LineChart struct {
+	Title   string
+	Series  []Series
+	Markers []Marker
+	// YMax - upper bound of the Y axis, 0 means the maximum of the values
+	YMax   float64
+	Width  int
+	Height int
+	// Percent - format the Y axis values as percentages (1 = 100 %)
+	Percent bool
+}
+
+// SVG - renders the bar chart as the standalone SVG element
+func (chart *BarChart) SVG() string {
+	canvas := newCanvas(chart.Width, chart.Height, chart.Title)
+	if len(chart.Bars) == 0 {
+		return canvas.empty()
+	}
+
+	yMax := 0.0
+	for _, bar := range chart.Bars {
+		yMax = math.Max(yMax, bar.Value)
+	}
+	yMax = niceCeil(yMax)
+	canvas.yAxis(yMax, false)
+
+	color := chart.Color
+	if color == "" {
+		color = Palette[0]
+	}
+
+	slot := canvas.plotWidth() / float64(len(chart.Bars))
+	labelEvery := int(math.Ceil(float64(len(chart.Bars)) * 40 / canvas.plotWidth()))
+	for i, bar := range chart.Bars {
+		height := bar.Value / yMax * canvas.plotHeight()
+		x := canvas.left() + float64(i)*slot
+		canvas.printf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s: %s</title></rect>`,
+			x+slot*0.1, canvas.bottom()-height, slot*0.8, height, color, escape(bar.Label), formatValue(bar.Value, false))
+		if i%labelEvery == 0 {
+			canvas.printf(`<text x="%.1f" y="%.1f" text-anchor="middle" class="tick">%s</text>`,
+				x+slot/2, canvas.bottom()+14, escape(bar.Label))
+		}
+	}
+
+	return canvas.close()
+}
+
+// SVG - renders the line chart as the standalone SVG element
+func (chart *LineChart) SVG() string {
+	canvas := newCanvas(chart.Width, chart.Height, chart.Title)
+
+	var from, to time.Time
+	yMax := chart.YMax
+	points := 0
+	for _, series := range chart.Series {
+		for _, point := range series.Points {
+			if from.IsZero() || point.Time.Before(from) {
+				from = point.Time
+			}
+			if to.IsZero() || point.Time.After(to) {
+				to = point.Time
+			}
+			if chart.YMax == 0 {
+				yMax = math.Max(yMax, point.Value)
+			}
+			points++
+		}
+	}
+	if points == 0 {
+		return canvas.empty()
+	}
+
+	for _, marker := range chart.Markers {
+		if marker.Time.Before(from) {
+			from = marker.Time
+		}
+		if marker.Time.After(to) {
+			to = marker.Time
+		}
+	}
+	if !to.After(from) {
+		from, to = from.Add(-12*time.Hour), to.Add(12*time.Hour)
+	}
+	if chart.YMax == 0 {
+		yMax = niceCeil(yMax)
+	}
+
+	canvas.yAxis(yMax, chart.Percent)
+	canvas.timeAxis(from, to)
+
+	x := func(t time.Time) float64 {
+		return canvas.left() + float64(t.Sub(from))/float64(to.Sub(from))*canvas.plotWidth()
+	}
+	y := func(value float64) float64 {
+		return canvas.bottom() - value/yMax*canvas.plotHeight()
+	}
+
+	for _, marker := range chart.Markers {
+		canvas.printf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" class="marker"/>`,
+			x(marker.Time), canvas.top(), x(marker.Time), canvas.bottom())
+		canvas.printf(`<text x="%.1f" y="%.1f" text-anchor="end" class="marker-label">%s</text>`,
+			x(marker.Time)-4, canvas.top()+12, escape(marker.Label))
+	}
+
+	for i, series := range chart.Series {
+		color := series.Color
+		if color == "" {
+			color = Palette[i%len(Palette)]
+		}
+
+		coordinates := make([]string, len(series.Points))
+		for j, point := range series.Points {
+			coordinates[j] = fmt.Sprintf("%.1f,%.1f", x(point.Time), y(point.Value))
+		}
+		canvas.printf(`<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`, strings.Join(coordinates, " "), color)
+		for _, point := range series.Points {
+			canvas.printf(`<circle cx="%.1f" cy="%.1f" r="2.5" fill="%s"><title>%s: %s</title></circle>`,
+				x(point.Time), y(point.Value), color, point.Time.Format("2006-01-02 15:04"), formatValue(point.Value, chart.Percent))
+		}
+
+		if len(chart.Series) > 1 {
+			canvas.printf(`<rect x="%.1f" y="%d" width="10" height="10" fill="%s"/><text x="%.1f" y="%d" class="legend">%s</text>`,
+				canvas.left()+float64(i)*120, canvas.height-12, color, canvas.left()+float64(i)*120+14, canvas.height-3, escape(series.Name))
+		}
+	}
+
+	return canvas.close()
+}
+
+// canvas - SVG document being rendered
+type canvas struct {
+	builder strings.Builder
+	width   int
+	height  int
+}
+
+func newCanvas(width, height int, title string) *canvas {
+	if width <= 0 {
+		width = DefaultWidth
+	}
+	if height <= 0 {
+		height = DefaultHeight
+	}
+
+	c := &canvas{width: width, height: height}
+	c.printf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" class="chart" role="img" aria-label="%s">`,
+		width, height, width, height, escape(title))
+	c.printf(`<style>text{font:11px sans-serif;fill:#444}.title{font-size:13px;font-weight:bold}.grid{stroke:#e5e5e5}.axis{stroke:#888}.marker{stroke:#d62728;stroke-dasharray:4 3}.marker-label{fill:#d62728}</style>`)
+	c.printf(`<text x="%d" y="18" class="title">%s</text>`, marginLeft, escape(title))
+	return c
+}
+
+func (c *canvas) printf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(&c.builder, format, args...)
+}
+
+func (c *canvas) left() float64       { return marginLeft }
+func (c *canvas) top() float64        { return marginTop }
+func (c *canvas) bottom() float64     { return float64(c.height - marginBottom) }
+func (c *canvas) plotWidth() float64  { return float64(c.width - marginLeft - marginRight) }
+func (c *canvas) plotHeight() float64 { return c.bottom() - c.top() }
+
+// yAxis - horizontal grid lines with the value labels
+func (c *canvas) yAxis(yMax float64, percent bool) {
+	ticks := tickCount(yMax)
+	for i := 0; i <= ticks; i++ {
+		value := yMax * float64(i) / float64(ticks)
+		y := c.bottom() - float64(i)/float64(ticks)*c.plotHeight()
+		c.printf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" class="grid"/>`, c.left(), y, c.left()+c.plotWidth(), y)
+		c.printf(`<text x="%.1f" y="%.1f" text-anchor="end" class="tick">%s</text>`, c.left()-6, y+4, formatValue(value, percent))
+	}
+	c.printf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" class="axis"/>`, c.left(), c.bottom(), c.left()+c.plotWidth(), c.bottom())
+}
+
+// timeAxis - date labels below the plot area
+func (c *canvas) timeAxis(from, to time.Time) {
+	const ticks = 4
+	layout := "01-02"
+	if to.Sub(from) < 48*time.Hour {
+		layout = "01-02 15:04"
+	}
+
+	for i := 0; i <= ticks; i++ {
+		t := from.Add(time.Duration(float64(to.Sub(from)) * float64(i) / ticks))
+		x := c.left() + float64(i)/ticks*c.plotWidth()
+		c.printf(`<text x="%.1f" y="%.1f" text-anchor="middle" class="tick">%s</text>`, x, c.bottom()+14, t.Format(layout))
+	}
+}
+
+func (c *canvas) empty() string {
+	c.printf(`<text x="%d" y="%d" text-anchor="middle">no data</text>`, c.width/2, c.height/2)
+	return c.close()
+}
+
+func (c *canvas) close() string {
+	c.printf(`</svg>`)
+	return c.builder.String()
+}
+
+// niceCeil - rounds the axis maximum up to 1, 2 or 5 times the power of ten
+func niceCeil(value float64) float64 {
+	if value <= 0 {
+		return 1
+	}
+
+	magnitude := math.Pow(10, math.Floor(math.Log10(value)))
+	for _, step := range []float64{1, 2, 5, 10} {
+		if value <= step*magnitude {
+			return step * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// tickCount - number of the Y axis steps, the whole step values are preferred
+func tickCount(yMax float64) int {
+	for _, ticks := range []int{4, 5, 2} {
+		if step := yMax / float64(ticks); step == math.Trunc(step) {
+			return ticks
+		}
+	}
+	return 4
+}
+
+func formatValue(value float64, percent bool) string {
+	if percent {
+		return fmt.Sprintf("%.0f %%", value*100)
+	}
+	if value == math.Trunc(value) {
+		return fmt.Sprintf("%.0f", value)
+	}
+	return fmt.Sprintf("%.2f", value)
+}
+
+func escape(text string) string {
+	return html.EscapeString(text)
+}
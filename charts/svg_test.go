@@ -0,0 +1,87 @@
+package charts
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func assertWellFormed(t *testing.T, svg string) {
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		_, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				return
+			}
+			t.Fatalf("FAIL: SVG is not well-formed: %v\n%s", err, svg)
+		}
+	}
+}
+
+func TestBarChart_SVG(t *testing.T) {
+	// GIVEN
+	chart := BarChart{Title: "Points <final>", Bars: []Bar{{Label: "0", Value: 1}, {Label: "1", Value: 3}}}
+
+	// WHEN
+	svg := chart.SVG()
+
+	// THEN
+	assertWellFormed(t, svg)
+	if strings.Count(svg, "<rect") != 2 {
+		t.Errorf("FAIL: Expected 2 bars in %s", svg)
+	}
+	if !strings.Contains(svg, "Points &lt;final&gt;") {
+		t.Errorf("FAIL: Title should be escaped in %s", svg)
+	}
+}
+
+func TestLineChart_SVG(t *testing.T) {
+	// GIVEN
+	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	chart := LineChart{
+		Title: "Submissions",
+		Series: []Series{
+			{Name: "all", Points: []TimePoint{{Time: start, Value: 1}, {Time: start.AddDate(0, 0, 1), Value: 4}}},
+		},
+		Markers: []Marker{{Time: start.AddDate(0, 0, 3), Label: "deadline"}},
+	}
+
+	// WHEN
+	svg := chart.SVG()
+
+	// THEN
+	assertWellFormed(t, svg)
+	if !strings.Contains(svg, "<polyline") || !strings.Contains(svg, "deadline") {
+		t.Errorf("FAIL: Expected the line and the deadline marker in %s", svg)
+	}
+}
+
+func TestCharts_Empty(t *testing.T) {
+	for _, svg := range []string{(&BarChart{}).SVG(), (&LineChart{}).SVG()} {
+		assertWellFormed(t, svg)
+		if !strings.Contains(svg, "no data") {
+			t.Errorf("FAIL: Empty chart should say so: %s", svg)
+		}
+	}
+}
+
+func TestTickCount(t *testing.T) {
+	cases := map[float64]int{4: 4, 100: 4, 5: 5, 10: 5, 2: 2, 1: 4, 0.5: 4}
+	for yMax, expected := range cases {
+		if actual := tickCount(yMax); actual != expected {
+			t.Errorf("FAIL: tickCount(%v) = %v, expected %v", yMax, actual, expected)
+		}
+	}
+}
+
+func TestNiceCeil(t *testing.T) {
+	cases := map[float64]float64{0: 1, 3: 5, 7: 10, 12: 20, 0.3: 0.5, 100: 100}
+	for value, expected := range cases {
+		if actual := niceCeil(value); actual != expected {
+			t.Errorf("FAIL: niceCeil(%v) = %v, expected %v", value, actual, expected)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package charts
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// sparkBlocks - the sparkline levels from the lowest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// barBlocks - the partial blocks of the bar end, in eighths
+var barBlocks = []rune(" ▏▎▍▌▋▊▉")
+
+// Sparkline - one line of the block characters, the highest value is the full block
+//
+// The zero values are drawn as the lowest block, so the gaps are visible as the flat line.
+func Sparkline(values []float64) string {
+	max := 0.0
+	for _, value := range values {
+		max = math.Max(max, value)
+	}
+
+	var builder strings.Builder
+	for _, value := range values {
+		level := 0
+		if max > 0 {
+			level = int(math.Round(value / max * float64(len(sparkBlocks)-1)))
+		}
+		builder.WriteRune(sparkBlocks[level])
+	}
+	return builder.String()
+}
+
+// HorizontalBars - bar chart with one labeled bar per line, the longest bar has the width characters
+func HorizontalBars(bars []Bar, width int) string {
+	max := 0.0
+	labelWidth := 0
+	for _, bar := range bars {
+		max = math.Max(max, bar.Value)
+		if length := utf8.RuneCountInString(bar.Label); length > labelWidth {
+			labelWidth = length
+		}
+	}
+
+	var builder strings.Builder
+	for _, bar := range bars {
+		length := 0.0
+		if max > 0 {
+			length = bar.Value / max * float64(width)
+		}
+		fmt.Fprintf(&builder, "%*s %s %s\n", labelWidth, bar.Label, bar.blocks(length), formatValue(bar.Value, false))
+	}
+	return builder.String()
+}
+
+// blocks - the full blocks followed by the partial block of the length in characters
+func (bar *Bar) blocks(length float64) string {
+	eighths := int(math.Round(length * 8))
+	blocks := strings.Repeat("█", eighths/8)
+	if eighths%8 > 0 {
+		blocks += string(barBlocks[eighths%8])
+	}
+	return blocks
+}
+
+// heatBlocks - the heatmap shades from the lowest
+var heatBlocks = []rune(" ░▒▓█")
+
+// Heatmap - one line of the shade characters per row, the highest value of all the rows is the full block
+func Heatmap(rows [][]float64) []string {
+	max := 0.0
+	for _, row := range rows {
+		for _, value := range row {
+			max = math.Max(max, value)
+		}
+	}
+
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		var builder strings.Builder
+		for _, value := range row {
+			level := 0
+			if max > 0 && value > 0 {
+				// rounded up, so any non-zero value is visible
+				level = int(math.Ceil(value / max * float64(len(heatBlocks)-1)))
+			}
+			builder.WriteRune(heatBlocks[level])
+		}
+		lines[i] = builder.String()
+	}
+	return lines
+}
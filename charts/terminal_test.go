@@ -0,0 +1,60 @@
+package charts
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSparkline(t *testing.T) {
+	// WHEN
+	line := Sparkline([]float64{0, 1, 2, 3, 4, 5, 6, 7})
+
+	// THEN
+	if line != "▁▂▃▄▅▆▇█" {
+		t.Errorf("FAIL: Unexpected sparkline '%s'", line)
+	}
+	if Sparkline([]float64{0, 0}) != "▁▁" {
+		t.Errorf("FAIL: All zeros should be the flat line")
+	}
+}
+
+func TestHorizontalBars(t *testing.T) {
+	// WHEN
+	chart := HorizontalBars([]Bar{{Label: "0", Value: 4}, {Label: "10", Value: 1}, {Label: "2", Value: 0}}, 8)
+
+	// THEN
+	lines := strings.Split(strings.TrimSuffix(chart, "\n"), "\n")
+	expected := []string{
+		" 0 ████████ 4",
+		"10 ██ 1",
+		" 2  0",
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("FAIL: Expected %d lines, got:\n%s", len(expected), chart)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("FAIL: Line %d is '%s', expected '%s'", i, lines[i], expected[i])
+		}
+	}
+}
+
+func TestHorizontalBars_PartialBlock(t *testing.T) {
+	// WHEN
+	chart := HorizontalBars([]Bar{{Label: "a", Value: 8}, {Label: "b", Value: 3}}, 2)
+
+	// THEN
+	if !strings.Contains(chart, "b ▊ 3") {
+		t.Errorf("FAIL: Expected the partial block:\n%s", chart)
+	}
+}
+
+func TestHeatmap(t *testing.T) {
+	// WHEN
+	lines := Heatmap([][]float64{{0, 1, 8}, {4, 0, 0}})
+
+	// THEN
+	if len(lines) != 2 || lines[0] != " ░█" || lines[1] != "▒  " {
+		t.Errorf("FAIL: Unexpected heatmap %q", lines)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/pestanko/isstat/app"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// apiCmd represents the api command
+var apiCmd = &cobra.Command{
+	Use:   "api",
+	Short: "Serve the results over the read-only REST API and the web dashboard",
+	Long: `Serve the results (raw XML, parsed JSON, CSV and the statistics) over the read-only HTTP API:
+
+	GET /api/notepads                                  notepads with the number of snapshots
+	GET /api/notepads/NOTEPAD                          snapshots of the notepad
+	GET /api/notepads/NOTEPAD/TIMESTAMP.FORMAT         xml, json or csv of the snapshot ("latest" timestamp)
+	GET /api/latest                                    the latest snapshot per notepad
+	GET /api/stats?match=PATTERN                       summaries of the parsed notepads
+
+The web dashboard with the per-notepad charts (score distribution, submissions over time,
+cumulative submissions against the deadline, students finished) is served on "/".
+The deadlines are taken from the config (notepads.NOTEPAD.deadline).
+
+The token (api.token or api.token_file) is required, the requests have to provide
+the "Authorization: Bearer TOKEN" header, the browsers may open the dashboard
+with "?token=TOKEN" once (the token is then moved to the cookie). The API refuses to start without the token
+unless --insecure is used. The API listens only on the localhost by default. For example:
+
+	isstat api --token-file ~/.config/isstat/api-token
+	isstat api --listen :8080 --token-file ~/.config/isstat/api-token`,
+	Run: executeAPI,
+}
+
+func init() {
+	rootCmd.AddCommand(apiCmd)
+
+	apiCmd.Flags().String("listen", "", "address to listen on (default \"127.0.0.1:8080\")")
+	apiCmd.Flags().String("token-file", "", "file with the bearer token required by the API")
+	apiCmd.Flags().Bool("insecure", false, "serve the API without the authentication when no token is configured")
+
+	_ = viper.BindPFlag("api.listen", apiCmd.Flags().Lookup("listen"))
+	_ = viper.BindPFlag("api.token_file", apiCmd.Flags().Lookup("token-file"))
+	_ = viper.BindPFlag("api.insecure", apiCmd.Flags().Lookup("insecure"))
+}
+
+func executeAPI(cmd *cobra.Command, args []string) {
+	config, err := app.GetAppConfig()
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	application, err := app.GetApplication(&config)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+	defer application.Close()
+
+	token, err := config.API.LoadToken()
+	if err != nil {
+		fmt.Printf("error: unable to load the api token: %v", err)
+		os.Exit(1)
+	}
+	if token == "" {
+		if !config.API.Insecure {
+			fmt.Printf("error: no api token configured (api.token or api.token_file), use --insecure to serve the API without the authentication")
+			os.Exit(1)
+		}
+		log.Warning("No API token configured, the API is not authenticated (--insecure)")
+	}
+
+	server := &http.Server{
+		Addr:              config.API.Listen,
+		Handler:           app.NewAPIServer(&application, token).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	stopped := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		defer close(stopped)
+		sig := <-signals
+		log.WithField("signal", sig).Warning("Received signal, shutting down")
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+	}()
+
+	log.WithField("listen", config.API.Listen).WithField("results", application.Results.Location()).Warning("Starting the API")
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+	// wait for the running requests before closing the results
+	<-stopped
+}
@@ -0,0 +1,87 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pestanko/isstat/app"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	changesAllFlag   bool
+	changesSinceFlag string
+)
+
+// changesCmd represents the changes command
+var changesCmd = &cobra.Command{
+	Use:   "changes NOTEPAD...",
+	Short: "Print the changes between the parsed notepad snapshots as JSON Lines",
+	Long: `Compare the consecutive parsed snapshots of the notepads and print the change events
+as JSON Lines (one JSON object per line).
+
+Event types: new_submission, points_changed, became_final, student_appeared,
+student_disappeared and edited_by_other (the entry was changed by an account
+not listed in the kontr_accounts config when the snapshot was parsed).
+
+By default only the last two snapshots are compared. For example:
+
+	isstat changes hw01 hw02
+	isstat changes --all --since 2020-03-01T00-00-00 hw01`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  executeChanges,
+}
+
+func init() {
+	rootCmd.AddCommand(changesCmd)
+
+	changesCmd.Flags().BoolVar(&changesAllFlag, "all", false, "compare all the consecutive snapshots")
+	changesCmd.Flags().StringVar(&changesSinceFlag, "since", "", "only the changes in the snapshots newer than the timestamp")
+}
+
+func executeChanges(cmd *cobra.Command, args []string) {
+	config, err := app.GetAppConfig()
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	application, err := app.GetApplication(&config)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+	defer application.Close()
+
+	encoder := json.NewEncoder(os.Stdout)
+	for _, notepad := range args {
+		events, err := application.Changes(notepad, changesSinceFlag, changesAllFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %s: %v\n", notepad, err)
+			os.Exit(1)
+		}
+
+		for _, event := range events {
+			if err := encoder.Encode(event); err != nil {
+				fmt.Fprintf(os.Stderr, "error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	}
+}
@@ -46,6 +46,7 @@ to quickly create a Cobra application.`,
 			fmt.Printf("error: %v", err)
 			os.Exit(1)
 		}
+		defer application.Close()
 
 		items, err := application.CleanResults(args, limit)
 		if err != nil {
@@ -18,39 +18,70 @@ package cmd
 import (
 	"fmt"
 	"github.com/pestanko/isstat/app"
+	"github.com/pestanko/isstat/core"
+	"io/ioutil"
 	"os"
 
 	"github.com/spf13/cobra"
 )
 
+// CSV layouts
+const (
+	csvLayoutLong = "long"
+	csvLayoutWide = "wide"
+)
+
+var (
+	csvLayoutFlag string
+	csvPivotFlag  string
+	csvPointsFlag string
+	csvOutFlag    string
+)
+
 // csvCmd represents the csv command
 var csvCmd = &cobra.Command{
-	Use:   "csv",
+	Use:   "csv PATTERN...",
 	Short: "Dump notepads as CSV files",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
-
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
+	Long: `Dump the parsed notepads (json results) as CSV files, one row per submission.
+
+The wide layout pivots the latest parsed snapshots of the notepads matching the patterns
+to one CSV file (the standard output by default) with one row per student:
+the attempt pivot has one row per notepad and student and one column per submission index,
+the notepad pivot has one column per notepad with the final or the best points.
+The wide layout uses the global CSV config without the columns and the date formats.
+
+The shape of the CSV is configured in the config file, globally and per notepad
+(the notepad's fields override the global ones):
+
+	csv:
+	  delimiter: ";"
+	  decimal: ","
+	  bom: true
+	notepads:
+	  hw01:
+	    csv:
+	      columns: [student_id, datetime, points]
+	      headers: {student_id: Student, points: Body}
+	      datetime_format: "02.01.2006 15:04"
+	      quote: all
+
+The columns are student_id, index, datetime, date, time, points, bonus and final. For example:
+
+	isstat csv 'hw*'
+	isstat csv --layout wide --pivot attempt --out hw01.csv hw01
+	isstat csv --layout wide --pivot notepad --points best --out homeworks.csv 'hw*'`,
 	Run: executeCSV,
 }
 
 func init() {
 	rootCmd.AddCommand(csvCmd)
 
-	// Here you will define your flags and configuration settings.
-
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// csvCmd.PersistentFlags().String("foo", "", "A help for foo")
-
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// csvCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	csvCmd.Flags().StringVar(&csvLayoutFlag, "layout", csvLayoutLong, "CSV layout: long (row per submission) or wide (row per student)")
+	csvCmd.Flags().StringVar(&csvPivotFlag, "pivot", core.PivotAttempt, "columns of the wide layout: attempt or notepad")
+	csvCmd.Flags().StringVar(&csvPointsFlag, "points", core.WidePointsFinal, "points of the notepad pivot: final or best")
+	csvCmd.Flags().StringVar(&csvOutFlag, "out", "", "output file of the wide layout (default stdout)")
 }
 
-
 func executeCSV(cmd *cobra.Command, args []string) {
 	config, err := app.GetAppConfig()
 	if err != nil {
@@ -63,6 +94,20 @@ func executeCSV(cmd *cobra.Command, args []string) {
 		fmt.Printf("error: %v", err)
 		os.Exit(1)
 	}
+	defer application.Close()
+
+	switch csvLayoutFlag {
+	case csvLayoutLong:
+	case csvLayoutWide:
+		if err := writeWideCSV(&application, args); err != nil {
+			fmt.Printf("error: %v", err)
+			os.Exit(1)
+		}
+		return
+	default:
+		fmt.Printf("error: unknown CSV layout '%s'", csvLayoutFlag)
+		os.Exit(1)
+	}
 
 	items, err := application.ConvertToCSV(args)
 	if err != nil {
@@ -70,8 +115,26 @@ func executeCSV(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	fmt.Printf("CSV was successful, result stored in %s\n", application.Results.ResultsDir)
+	fmt.Printf("CSV was successful, result stored in %s\n", application.Results.Location())
 	for i, item := range items {
 		fmt.Printf("%d  %25s\n", i, item.GetFullName())
 	}
-}
\ No newline at end of file
+}
+
+func writeWideCSV(application *app.IsStatApp, patterns []string) error {
+	table, err := application.WideTable(patterns, csvPivotFlag, csvPointsFlag)
+	if err != nil {
+		return err
+	}
+
+	content, err := application.MarshalWide(&table)
+	if err != nil {
+		return err
+	}
+
+	if csvOutFlag == "" || csvOutFlag == "-" {
+		_, err = os.Stdout.Write(content)
+		return err
+	}
+	return ioutil.WriteFile(csvOutFlag, content, 0600)
+}
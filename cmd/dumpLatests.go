@@ -44,6 +44,7 @@ to quickly create a Cobra application.`,
 			fmt.Printf("error: %v", err)
 			os.Exit(1)
 		}
+		defer application.Close()
 
 		_, _ = application.DumpLatest()
 	},
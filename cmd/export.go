@@ -0,0 +1,94 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/pestanko/isstat/app"
+	"github.com/pestanko/isstat/core"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormatFlag string
+	exportOutFlag    string
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export [NOTEPAD_PATTERN...]",
+	Short: "Export the parsed notepads as the Excel workbook",
+	Long: `Export the latest parsed snapshots of the notepads matching the glob patterns (all by default)
+as the Excel workbook, without any external tools.
+
+The workbook has the summary sheet with one row per notepad (statistics of the points,
+the finished share and the configured deadline) followed by one sheet per notepad
+with one row per submission. The cells are typed (numbers, dates in the local time, booleans),
+so there are no CSV encoding or locale problems. The header rows are frozen and have the autofilter.
+For example:
+
+	isstat export --out pb071.xlsx 'hw*'`,
+	Run: executeExport,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVar(&exportFormatFlag, "format", app.ExportXLSX, "export format: xlsx")
+	exportCmd.Flags().StringVar(&exportOutFlag, "out", "isstat.xlsx", "output file")
+}
+
+func executeExport(cmd *cobra.Command, args []string) {
+	if exportFormatFlag != app.ExportXLSX {
+		fmt.Printf("error: unknown export format '%s'", exportFormatFlag)
+		os.Exit(1)
+	}
+
+	config, err := app.GetAppConfig()
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	application, err := app.GetApplication(&config)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+	defer application.Close()
+
+	workbook, err := application.ExportWorkbook(args)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	var content bytes.Buffer
+	if err := workbook.Write(&content); err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+	// the existing workbook is replaced only by the complete one
+	if err := core.WriteFileAtomic(exportOutFlag, content.Bytes(), 0600); err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Workbook with %d sheets written to %s\n", len(workbook.Sheets), exportOutFlag)
+}
@@ -22,22 +22,32 @@ import (
 	"os"
 )
 
+var (
+	fetchAllFlag   bool
+	fetchMatchFlag []string
+)
+
 // fetchCmd represents the fetch command
 var fetchCmd = &cobra.Command{
-	Use:   "fetch",
+	Use:   "fetch [NOTEPAD...]",
 	Short: "Gets a notepad content",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
+	Long: `Gets the content of the provided notepads and stores it to the results directory.
+
+The notepads can be provided as arguments or discovered from the course,
+either all of them (--all) or the ones matching the glob pattern (--match). For example:
 
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
+	isstat fetch hw01 hw02
+	isstat fetch --match 'hw*' --match 'review*'
+	isstat fetch --all`,
 	Run: ExecuteCommand,
 }
 
 func init() {
 	rootCmd.AddCommand(fetchCmd)
 
+	fetchCmd.Flags().BoolVar(&fetchAllFlag, "all", false, "fetch all the notepads of the course")
+	fetchCmd.Flags().StringSliceVar(&fetchMatchFlag, "match", nil, "fetch the course notepads matching the glob pattern")
+
 	// Here you will define your flags and configuration settings.
 
 	// Cobra supports Persistent Flags which will work for this command
@@ -61,15 +71,52 @@ func ExecuteCommand(cmd *cobra.Command, args []string) {
 		fmt.Printf("error: %v", err)
 		os.Exit(1)
 	}
+	defer application.Close()
+
+	notepads, err := resolveNotepads(&application, args, fetchAllFlag, fetchMatchFlag)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
 
-	items, err := application.Fetch(args)
+	items, err := application.Fetch(notepads)
 	if err != nil {
 		fmt.Printf("error: %v", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("Fetch was successful, result stored in %s\n", application.Results.ResultsDir)
+	fmt.Printf("Fetch was successful, result stored in %s\n", application.Results.Location())
 	for i, item := range items {
 		fmt.Printf("%d  %25s\n", i, item.GetFullName())
 	}
 }
+
+// resolveNotepads - joins the notepads provided as arguments with the discovered ones
+func resolveNotepads(application *app.IsStatApp, args []string, all bool, match []string) ([]string, error) {
+	if !all && len(match) == 0 {
+		return args, nil
+	}
+
+	var patterns []string
+	if !all {
+		patterns = match
+	}
+
+	discovered, err := application.DiscoverNotepads(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	notepads := args
+	known := make(map[string]bool)
+	for _, notepad := range args {
+		known[notepad] = true
+	}
+	for _, notepad := range discovered {
+		if !known[notepad.Shortname] {
+			known[notepad.Shortname] = true
+			notepads = append(notepads, notepad.Shortname)
+		}
+	}
+	return notepads, nil
+}
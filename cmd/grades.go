@@ -0,0 +1,181 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/pestanko/isstat/app"
+	"github.com/pestanko/isstat/core"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// Output formats of the grades
+const (
+	gradesTable = "table"
+	gradesCSV   = "csv"
+	gradesJSON  = "json"
+)
+
+var (
+	gradesFormatFlag    string
+	gradesGradebookFlag string
+)
+
+// gradesCmd represents the grades command
+var gradesCmd = &cobra.Command{
+	Use:   "grades",
+	Short: "Compute the students' grades across the notepads",
+	Long: `Compute the students' totals and grades from the latest parsed snapshots
+of the notepads by the gradebook configured in the config file (or in the separate YAML file):
+
+	gradebook:
+	  notepads:
+	    - name: hw01
+	      weight: 1
+	      max_points: 10
+	    - name: exam
+	      weight: 2
+	  bonus: add            # add, cap (at the max points) or ignore
+	  rules:
+	    - type: best        # only the 5 best of the homeworks are counted
+	      count: 5
+	      notepads: ["hw*"]
+	    - type: min         # at least 50 % of the max points in each notepad
+	      share: 0.5
+	  grades:
+	    - {grade: A, min: 90}
+	    - {grade: B, min: 80}
+	    - {grade: F, min: 0}
+
+The students are joined across the notepads by their IDs, the total is the weighted mean
+of the counted notepads' scores in percent. The student violating the min rule gets the
+lowest grade. The uncounted scores are shown in the parentheses. For example:
+
+	isstat grades
+	isstat grades --gradebook pb071.yaml --format csv > grades.csv`,
+	Run: executeGrades,
+}
+
+func init() {
+	rootCmd.AddCommand(gradesCmd)
+
+	gradesCmd.Flags().StringVar(&gradesFormatFlag, "format", gradesTable, "output format: table, csv or json")
+	gradesCmd.Flags().StringVar(&gradesGradebookFlag, "gradebook", "", "gradebook YAML file (default is the gradebook in the config)")
+}
+
+func executeGrades(cmd *cobra.Command, args []string) {
+	config, err := app.GetAppConfig()
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	gradebook, err := loadGradebook(&config, gradesGradebookFlag)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	application, err := app.GetApplication(&config)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+	defer application.Close()
+
+	grades, err := application.Grades(gradebook)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	switch gradesFormatFlag {
+	case gradesTable:
+		printGradesTable(gradebook.NotepadNames(), grades)
+	case gradesCSV:
+		err = writeGradesCSV(gradebook.NotepadNames(), grades)
+	case gradesJSON:
+		var content []byte
+		if content, err = json.MarshalIndent(grades, "", "  "); err == nil {
+			fmt.Println(string(content))
+		}
+	default:
+		err = fmt.Errorf("unknown grades format '%s'", gradesFormatFlag)
+	}
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+}
+
+// loadGradebook - loads the gradebook from the file, or uses the configured one when the file is empty
+func loadGradebook(config *app.Config, file string) (core.Gradebook, error) {
+	if file == "" {
+		return config.Gradebook, nil
+	}
+	return core.LoadGradebook(file)
+}
+
+func printGradesTable(notepads []string, grades []core.StudentGrade) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "STUDENT\t%s\tTOTAL\tGRADE\tNOTES\n", strings.Join(notepads, "\t"))
+	for _, grade := range grades {
+		fmt.Fprintf(writer, "%s\t", grade.StudentID)
+		for _, notepad := range notepads {
+			score := grade.Scores[notepad]
+			switch {
+			case score.Missing:
+				fmt.Fprint(writer, "-\t")
+			case !score.Counted:
+				fmt.Fprintf(writer, "(%.2f)\t", score.Score)
+			default:
+				fmt.Fprintf(writer, "%.2f\t", score.Score)
+			}
+		}
+		fmt.Fprintf(writer, "%.2f %%\t%s\t%s\n", grade.Total, grade.Grade, strings.Join(grade.Failed, ", "))
+	}
+	_ = writer.Flush()
+}
+
+func writeGradesCSV(notepads []string, grades []core.StudentGrade) error {
+	writer := csv.NewWriter(os.Stdout)
+
+	header := append([]string{"student_id"}, notepads...)
+	if err := writer.Write(append(header, "total", "grade", "failed")); err != nil {
+		return err
+	}
+
+	for _, grade := range grades {
+		record := []string{grade.StudentID.String()}
+		for _, notepad := range notepads {
+			record = append(record, strconv.FormatFloat(grade.Scores[notepad].Score, 'f', -1, 64))
+		}
+		record = append(record, strconv.FormatFloat(grade.Total, 'f', 2, 64), grade.Grade, strings.Join(grade.Failed, "; "))
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
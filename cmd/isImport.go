@@ -0,0 +1,152 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"github.com/pestanko/isstat/app"
+	"github.com/pestanko/isstat/core"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	isImportValueFlag     string
+	isImportFormatFlag    string
+	isImportOutFlag       string
+	isImportAllowedFlag   []string
+	isImportNotepadFlag   string
+	isImportGradebookFlag string
+	isImportForceFlag     bool
+)
+
+// isImportCmd represents the is-import command
+var isImportCmd = &cobra.Command{
+	Use:   "is-import",
+	Short: "Prepare the IS bulk import file of the students' grades",
+	Long: `Prepare the file for the IS bulk import of the students' grades computed
+by the gradebook (see the grades command).
+
+The students are mapped back to their UCOs by the students register (register in the config),
+so only the users holding the register can prepare the import. The text format writes
+one "UCO:value" line per student as accepted by the IS notepad import from the text file,
+the csv format writes the uco,value columns (accepted by the push command too).
+The value is either the grade or the total percentage of the gradebook in the IS points
+notation ("*85.5" is 85.5 %, not the notepad points).
+
+The entries are always previewed and validated first: the students missing in the register,
+the invalid or duplicate UCOs, the UCOs not in the target notepad (with --notepad, read from the IS)
+and the grades not in the allowed ones. The file is written only with --out and only when all
+the students are mapped and valid, --force writes the valid entries anyway. For example:
+
+	isstat is-import
+	isstat is-import --notepad znamka --out grades.txt
+	isstat is-import --value total --format csv --out total.csv`,
+	Run: executeISImport,
+}
+
+func init() {
+	rootCmd.AddCommand(isImportCmd)
+
+	isImportCmd.Flags().StringVar(&isImportValueFlag, "value", core.ISValueGrade, "imported value: grade or total (percentage)")
+	isImportCmd.Flags().StringVar(&isImportFormatFlag, "format", core.ISImportText, "file format: text or csv")
+	isImportCmd.Flags().StringVar(&isImportOutFlag, "out", "", "output file (preview only by default)")
+	isImportCmd.Flags().StringSliceVar(&isImportAllowedFlag, "allowed", core.ISGrades, "grades accepted by the IS")
+	isImportCmd.Flags().StringVar(&isImportNotepadFlag, "notepad", "", "target notepad, its students are the known UCOs")
+	isImportCmd.Flags().StringVar(&isImportGradebookFlag, "gradebook", "", "gradebook YAML file (default is the gradebook in the config)")
+	isImportCmd.Flags().BoolVar(&isImportForceFlag, "force", false, "write the valid entries even if some are invalid")
+}
+
+func executeISImport(cmd *cobra.Command, args []string) {
+	config, err := app.GetAppConfig()
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	gradebook, err := loadGradebook(&config, isImportGradebookFlag)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	application, err := app.GetApplication(&config)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+	defer application.Close()
+
+	isImport, err := application.ISImport(gradebook, isImportValueFlag, isImportAllowedFlag, isImportNotepadFlag)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	invalid := printISImportPreview(&isImport)
+	if isImportOutFlag == "" {
+		return
+	}
+
+	if (invalid > 0 || len(isImport.Unmapped) > 0) && !isImportForceFlag {
+		fmt.Printf("error: %d invalid entries and %d students without UCO, fix them or use --force to write only the valid ones",
+			invalid, len(isImport.Unmapped))
+		os.Exit(1)
+	}
+
+	if err := writeISImport(&isImport, isImportOutFlag, isImportFormatFlag); err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Import of %d entries written to %s\n", len(isImport.Entries)-invalid, isImportOutFlag)
+}
+
+// printISImportPreview - prints the entries with their problems, it returns the number of the invalid ones
+func printISImportPreview(isImport *core.ISImport) int {
+	invalid := 0
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "UCO\tSTUDENT\t%s\tPROBLEMS\n", strings.ToUpper(isImport.Value))
+	for _, entry := range isImport.Entries {
+		if len(entry.Problems) > 0 {
+			invalid++
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", entry.Uco, entry.StudentID, entry.Value, strings.Join(entry.Problems, ", "))
+	}
+	_ = writer.Flush()
+
+	for _, id := range isImport.Unmapped {
+		fmt.Printf("Student %s is not in the students register\n", id)
+	}
+	fmt.Printf("Entries: %d valid, %d invalid, %d students without UCO\n",
+		len(isImport.Entries)-invalid, invalid, len(isImport.Unmapped))
+	return invalid
+}
+
+// writeISImport - writes the import file readable only by the owner, it contains the UCOs
+func writeISImport(isImport *core.ISImport, out, format string) error {
+	file, err := os.OpenFile(out, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := isImport.Write(file, format); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
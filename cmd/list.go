@@ -47,6 +47,7 @@ to quickly create a Cobra application.`,
 			fmt.Printf("error: %v", err)
 			os.Exit(1)
 		}
+		defer application.Close()
 
 		var items []core.ResultItem
 
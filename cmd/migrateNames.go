@@ -0,0 +1,80 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"github.com/pestanko/isstat/app"
+	"github.com/pestanko/isstat/core"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// migrateNamesCmd represents the migrate-names command
+var migrateNamesCmd = &cobra.Command{
+	Use:   "migrate-names",
+	Short: "Rename the results to the current naming scheme",
+	Long: `Rename the files in the results directory to the current naming scheme
+(<name>.<timestamp>.<ext> with the 2006-01-02T15-04-05 timestamp).
+
+The files with the legacy timestamps (for example 2020-03-01T10:00:00 or 20200301T100000)
+are renamed, the unrecognized files and the conflicts are reported and kept.
+Use --dry-run to only show the renames. For example:
+
+	isstat migrate-names --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := app.GetAppConfig()
+		if err != nil {
+			fmt.Printf("error: %v", err)
+			os.Exit(1)
+		}
+
+		application, err := app.GetApplication(&config)
+		if err != nil {
+			fmt.Printf("error: %v", err)
+			os.Exit(1)
+		}
+		defer application.Close()
+
+		store, ok := application.Results.Backend.(*core.FilesystemStore)
+		if !ok {
+			fmt.Printf("error: only the filesystem results can be migrated")
+			os.Exit(1)
+		}
+
+		migrations, err := core.MigrateResultNames(store.Dir, config.DryRun)
+		if err != nil {
+			fmt.Printf("error: %v", err)
+			os.Exit(1)
+		}
+
+		failed := 0
+		for i, migration := range migrations {
+			if migration.Error != "" {
+				failed++
+				fmt.Printf("%d  %s - %s\n", i, migration.From, migration.Error)
+				continue
+			}
+			fmt.Printf("%d  %s -> %s\n", i, migration.From, migration.To)
+		}
+		fmt.Printf("Migrated %d results in %s, %d failed\n", len(migrations)-failed, store.Dir, failed)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateNamesCmd)
+}
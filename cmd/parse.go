@@ -63,6 +63,7 @@ func executeParse(cmd *cobra.Command, args []string) {
 		fmt.Printf("error: %v", err)
 		os.Exit(1)
 	}
+	defer application.Close()
 
 	items, err := application.Parse(args)
 	if err != nil {
@@ -70,7 +71,7 @@ func executeParse(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	fmt.Printf("Parse was successful, result stored in %s\n", application.Results.ResultsDir)
+	fmt.Printf("Parse was successful, result stored in %s\n", application.Results.Location())
 	for key, item := range items {
 		fmt.Printf("Notepad: [%20s]\n", key)
 		for i, info := range item {
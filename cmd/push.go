@@ -0,0 +1,98 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"github.com/pestanko/isstat/app"
+	"github.com/pestanko/isstat/core"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// pushCmd represents the push command
+var pushCmd = &cobra.Command{
+	Use:   "push NOTEPAD FILE",
+	Short: "Write the computed content to the students' notepads",
+	Long: `Write the computed content to the students' notepads in the IS.
+
+The FILE contains the UCO keyed content, either JSON ({"uco": "content"}
+or [{"uco": "...", "content": "..."}]) or CSV with two columns (uco,content).
+Only the students whose content differs from the current notepad content are written.
+
+With --dry-run the current notepad content is read and the changes that would be
+written are printed per UCO, nothing is written. For example:
+
+	isstat push --dry-run total total.csv`,
+	Args: cobra.ExactArgs(2),
+	Run:  executePush,
+}
+
+func init() {
+	rootCmd.AddCommand(pushCmd)
+}
+
+func executePush(cmd *cobra.Command, args []string) {
+	config, err := app.GetAppConfig()
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	application, err := app.GetApplication(&config)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+	defer application.Close()
+
+	contents, err := core.LoadStudentContents(args[1])
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	changes, pushErr := application.Push(args[0], contents)
+
+	counts := make(map[string]int)
+	for _, change := range changes {
+		counts[change.Action]++
+		if !change.Changed() {
+			continue
+		}
+
+		status := "written"
+		if config.DryRun {
+			status = "dry-run"
+		} else if change.Error != "" {
+			status = "failed: " + change.Error
+		}
+
+		fmt.Printf("[%-6s] %s (%s)\n", change.Action, change.Uco, status)
+		if config.DryRun {
+			fmt.Print(change.Diff())
+		}
+	}
+
+	fmt.Printf("Notepad [%s]: %d to create, %d to update, %d unchanged\n",
+		args[0], counts[core.PushCreate], counts[core.PushUpdate], counts[core.PushUnchanged])
+
+	if pushErr != nil {
+		fmt.Printf("error: %v", pushErr)
+		os.Exit(1)
+	}
+}
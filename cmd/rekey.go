@@ -0,0 +1,85 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"github.com/pestanko/isstat/app"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var rekeyNewKeyFile string
+
+// rekeyCmd represents the rekey command
+var rekeyCmd = &cobra.Command{
+	Use:   "rekey [PATTERN...]",
+	Short: "Derive the students' pseudonyms from a new key",
+	Long: `Derive the students' pseudonyms from a new key (HMAC mode) and rewrite
+the parsed JSON snapshots matching the patterns (default all JSON results).
+
+The previous pseudonyms are taken from the students register, so the command
+also migrates the snapshots created with the random pseudonyms.
+After the re-keying update the pseudonym config to use the new key. For example:
+
+	isstat rekey --new-key-file ~/.config/isstat/pseudonym.key`,
+	Run: executeRekey,
+}
+
+func init() {
+	rootCmd.AddCommand(rekeyCmd)
+
+	rekeyCmd.Flags().StringVar(&rekeyNewKeyFile, "new-key-file", "", "file with the new pseudonym key")
+	_ = rekeyCmd.MarkFlagRequired("new-key-file")
+}
+
+func executeRekey(cmd *cobra.Command, args []string) {
+	config, err := app.GetAppConfig()
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	application, err := app.GetApplication(&config)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+	defer application.Close()
+
+	newKey, err := app.LoadKeyFile(rekeyNewKeyFile)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	if len(args) == 0 {
+		args = []string{"*.json*"}
+	}
+
+	items, err := application.Rekey(newKey, args)
+	for i, item := range items {
+		fmt.Printf("%d  %25s\n", i, item.GetFullName())
+	}
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Re-keying was successful, update the config to use the new key:\n\n")
+	fmt.Printf("pseudonym:\n  mode: %s\n  key_file: %s\n", app.PseudonymHMAC, rekeyNewKeyFile)
+}
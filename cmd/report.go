@@ -0,0 +1,114 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"github.com/pestanko/isstat/app"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportFormatFlag string
+	reportOutFlag    string
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report [NOTEPAD_PATTERN...]",
+	Short: "Generate the report of the parsed notepads",
+	Long: `Generate the report of the parsed notepads matching the glob patterns (all by default).
+
+The html format renders the standalone static report to the output directory:
+the overview page (index.html) and one page per notepad (notepad-NAME.html) with the inline SVG charts
+(score distribution, submissions over time, cumulative submissions against the deadline,
+students finished). The report does not need any external files and can be viewed offline.
+
+The markdown format writes the GitHub/GitLab flavoured Markdown with the tables
+of the notepads' statistics, attempts distributions and lateness (against the configured
+deadlines) to the output file, or to the standard output by default. For example:
+
+	isstat report --format html --out report/ 'hw*'
+	isstat report --format markdown --out wiki/weekly.md`,
+	Run: executeReport,
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+
+	reportCmd.Flags().StringVar(&reportFormatFlag, "format", app.ReportHTML, "report format: html or markdown")
+	reportCmd.Flags().StringVar(&reportOutFlag, "out", "", "output directory for html (default \"report\"), output file for markdown (default stdout)")
+}
+
+func executeReport(cmd *cobra.Command, args []string) {
+	config, err := app.GetAppConfig()
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	application, err := app.GetApplication(&config)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+	defer application.Close()
+
+	report, err := application.BuildReport(args)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	var files []string
+	switch reportFormatFlag {
+	case app.ReportHTML:
+		if reportOutFlag == "" {
+			reportOutFlag = "report"
+		}
+		files, err = report.WriteHTML(reportOutFlag)
+	case app.ReportMarkdown:
+		err = writeMarkdownReport(&report, reportOutFlag)
+	default:
+		err = fmt.Errorf("unknown report format '%s'", reportFormatFlag)
+	}
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	for _, file := range files {
+		fmt.Println(file)
+	}
+}
+
+func writeMarkdownReport(report *app.Report, out string) error {
+	if out == "" || out == "-" {
+		return report.WriteMarkdown(os.Stdout)
+	}
+
+	file, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := report.WriteMarkdown(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -65,19 +65,31 @@ func init() {
   rootCmd.PersistentFlags().StringP( "token", "T", "", "is muni token")
   rootCmd.PersistentFlags().StringP( "course", "C", "", "is muni course code")
   rootCmd.PersistentFlags().Int( "faculty-id", 0, "is muni faculty id")
+  rootCmd.PersistentFlags().Int( "timeout", 0, "is muni request timeout in seconds (default 30)")
+  rootCmd.PersistentFlags().Int( "retries", 0, "number of retries of the failed is muni request (default 3)")
+  rootCmd.PersistentFlags().Float64( "rate-limit", 0, "max number of is muni requests per second (default 2)")
   rootCmd.PersistentFlags().String( "parser", "", "parser for parsing the muni notepad content")
-  rootCmd.PersistentFlags().String( "results", "", "results directory (default $CWD)")
+  rootCmd.PersistentFlags().String( "results", "", "results directory or database file (default $CWD)")
+  rootCmd.PersistentFlags().String( "storage", "", "results storage: filesystem or bolt (default filesystem)")
+  rootCmd.PersistentFlags().String( "register", "", "students register file (default is $HOME/.config/isstat/students-register.json)")
   rootCmd.PersistentFlags().Bool( "dry-run", false, "dry run - do not execute the request")
   rootCmd.PersistentFlags().Bool( "without-timestamp", false, "create also without timestamp")
+  rootCmd.PersistentFlags().Int( "workers", 0, "number of parallel workers fetching the notepads (default 4)")
 
   _ = viper.BindPFlag("muni.url", rootCmd.PersistentFlags().Lookup("url"))
   _ = viper.BindPFlag("muni.token", rootCmd.PersistentFlags().Lookup("token"))
   _ = viper.BindPFlag("muni.course", rootCmd.PersistentFlags().Lookup("course"))
   _ = viper.BindPFlag("muni.faculty", rootCmd.PersistentFlags().Lookup("faculty-id"))
+  _ = viper.BindPFlag("muni.timeout", rootCmd.PersistentFlags().Lookup("timeout"))
+  _ = viper.BindPFlag("muni.retries", rootCmd.PersistentFlags().Lookup("retries"))
+  _ = viper.BindPFlag("muni.rate_limit", rootCmd.PersistentFlags().Lookup("rate-limit"))
   _ = viper.BindPFlag("results", rootCmd.PersistentFlags().Lookup("results"))
+  _ = viper.BindPFlag("register", rootCmd.PersistentFlags().Lookup("register"))
+  _ = viper.BindPFlag("storage", rootCmd.PersistentFlags().Lookup("storage"))
   _ = viper.BindPFlag("parser", rootCmd.PersistentFlags().Lookup("parser"))
   _ = viper.BindPFlag("dryrun", rootCmd.PersistentFlags().Lookup("dry-run"))
   _ = viper.BindPFlag("without_timestamp", rootCmd.PersistentFlags().Lookup("without-timestamp"))
+  _ = viper.BindPFlag("workers", rootCmd.PersistentFlags().Lookup("workers"))
 
 }
 
@@ -0,0 +1,85 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pestanko/isstat/app"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	runsLastFlag    int
+	runsJSONFlag    bool
+	runsHistoryFlag string
+)
+
+// runsCmd represents the runs command
+var runsCmd = &cobra.Command{
+	Use:   "runs",
+	Short: "Show the history of the daemon runs",
+	Long: `Show the history of the runs of the "isstat serve" daemon. For example:
+
+	isstat runs --last 10`,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := app.GetAppConfig()
+		if err != nil {
+			fmt.Printf("error: %v", err)
+			os.Exit(1)
+		}
+
+		history := config.Serve.History
+		if runsHistoryFlag != "" {
+			history = runsHistoryFlag
+		}
+
+		records, err := app.NewRunHistory(history).Load(runsLastFlag)
+		if err != nil {
+			fmt.Printf("error: %v", err)
+			os.Exit(1)
+		}
+
+		if runsJSONFlag {
+			encoder := json.NewEncoder(os.Stdout)
+			for _, record := range records {
+				_ = encoder.Encode(record)
+			}
+			return
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "START\tDURATION\tSTATUS\tNOTEPADS\tFAILED\tERROR")
+		for _, record := range records {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%d\t%d\t%s\n",
+				record.Start.Format("2006-01-02 15:04:05"), record.End.Sub(record.Start).Round(time.Millisecond),
+				record.Status, record.Notepads, record.Failed, record.Error)
+		}
+		_ = writer.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(runsCmd)
+
+	runsCmd.Flags().IntVar(&runsLastFlag, "last", 20, "number of the last runs to show (0 for all)")
+	runsCmd.Flags().BoolVar(&runsJSONFlag, "json", false, "print the runs as JSON Lines")
+	runsCmd.Flags().StringVar(&runsHistoryFlag, "history", "", "runs history file (default is $HOME/.config/isstat/runs.jsonl)")
+}
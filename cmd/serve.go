@@ -0,0 +1,123 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/pestanko/isstat/app"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAllFlag         bool
+	serveMatchFlag       []string
+	serveImmediatelyFlag bool
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve [NOTEPAD...]",
+	Short: "Run the sync pipeline on the schedule",
+	Long: `Run the sync pipeline (fetch, parse, csv, stats) on the cron schedule
+as the long-running process.
+
+The run is skipped when the previous one is still running, SIGTERM (or SIGINT)
+stops the scheduling and waits for the running run to finish.
+Each run is recorded to the history file, use "isstat runs" to query it.
+
+With --adaptive the notepads with the configured deadline (notepads.<name>.deadline)
+are fetched more often close to the deadline and shortly after it,
+the schedule then has to be fine-grained (every minute). For example:
+
+	isstat serve --schedule "*/15 * * * *" --match 'hw*'
+	isstat serve --schedule "* * * * *" --adaptive --match 'hw*'`,
+	Run: executeServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().String("schedule", "", "cron expression of the runs (default \"*/15 * * * *\")")
+	serveCmd.Flags().String("history", "", "runs history file (default is $HOME/.config/isstat/runs.jsonl)")
+	serveCmd.Flags().BoolVar(&serveAllFlag, "all", false, "sync all the notepads of the course")
+	serveCmd.Flags().StringSliceVar(&serveMatchFlag, "match", nil, "sync the course notepads matching the glob pattern")
+	serveCmd.Flags().BoolVar(&serveImmediatelyFlag, "immediately", false, "run the pipeline right after the start")
+	serveCmd.Flags().Bool("adaptive", false, "fetch the notepads more often close to their deadlines")
+
+	_ = viper.BindPFlag("serve.schedule", serveCmd.Flags().Lookup("schedule"))
+	_ = viper.BindPFlag("serve.history", serveCmd.Flags().Lookup("history"))
+	_ = viper.BindPFlag("serve.adaptive", serveCmd.Flags().Lookup("adaptive"))
+}
+
+func executeServe(cmd *cobra.Command, args []string) {
+	config, err := app.GetAppConfig()
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	application, err := app.GetApplication(&config)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+	defer application.Close()
+
+	if err := os.MkdirAll(filepath.Dir(config.Serve.History), 0700); err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	notepads := func() ([]string, error) {
+		return resolveNotepads(&application, args, serveAllFlag, serveMatchFlag)
+	}
+
+	daemon, err := app.NewDaemon(&application, config.Serve.Schedule, notepads, app.NewRunHistory(config.Serve.History))
+	if err != nil {
+		fmt.Printf("error: invalid schedule '%s': %v", config.Serve.Schedule, err)
+		os.Exit(1)
+	}
+
+	if config.Serve.Adaptive {
+		daemon.Policy, err = app.NewAdaptivePolicyFromConfig(&config)
+		if err != nil {
+			fmt.Printf("error: %v", err)
+			os.Exit(1)
+		}
+		daemon.LastFetch = application.LastSnapshotTime
+		log.WithField("deadlines", len(daemon.Policy.Deadlines)).Warning("Adaptive fetch frequency enabled")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		sig := <-signals
+		log.WithField("signal", sig).Warning("Received signal, shutting down")
+		cancel()
+	}()
+
+	log.WithField("schedule", config.Serve.Schedule).WithField("history", config.Serve.History).Warning("Starting the daemon")
+	daemon.Run(ctx, serveImmediatelyFlag)
+}
@@ -16,36 +16,214 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/pestanko/isstat/app"
+	"github.com/pestanko/isstat/charts"
+	"github.com/pestanko/isstat/core"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	statsJSONFlag     bool
+	statsChartFlag    bool
+	statsAttemptsFlag bool
+	statsGiveUpFlag   int
+)
+
+// statsChartWidth - width of the longest bar of the terminal charts
+const statsChartWidth = 40
+
+// hourAxis - labels below the 24 characters wide hour-of-day charts
+const hourAxis = "0     6     12    18   23"
+
 // statsCmd represents the stats command
 var statsCmd = &cobra.Command{
 	Use:   "stats",
-	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains examples
-and usage of using your command. For example:
+	Short: "Show the score summaries of the parsed notepads",
+	Long: `Show the score summaries of the parsed notepads (JSON results).
 
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("stats called")
-	},
+For each parsed notepad snapshot matching the provided patterns it prints
+the number of students and submissions, mean, median, standard deviation,
+minimum and maximum of the final points and bonus and the share of the students
+with a final submission.
+
+With --chart it also draws the histogram of the final points and the sparklines
+of the submissions per day and per hour of the day (in the local time).
+
+With --attempts it reports how many attempts the students needed to reach the pass
+and the full points (notepads.NOTEPAD.pass_points and full_points in the config,
+the half of the max points and the max points by default), the distribution of the attempts,
+the mean points gained per attempt and the students who gave up after the failing attempts.
+The passed and full students are not computed when the full points are zero. For example:
+
+	isstat stats 'hw01.*.json'
+	isstat stats --chart hw01.json
+	isstat stats --attempts --give-up 3 'hw*.json'`,
+	Run: executeStats,
 }
 
 func init() {
 	rootCmd.AddCommand(statsCmd)
 
-	// Here you will define your flags and configuration settings.
+	statsCmd.Flags().BoolVar(&statsJSONFlag, "json", false, "print the summaries as JSON")
+	statsCmd.Flags().BoolVar(&statsChartFlag, "chart", false, "draw the terminal charts of the points and the submissions")
+	statsCmd.Flags().BoolVar(&statsAttemptsFlag, "attempts", false, "analyse the attempts the students needed to succeed")
+	statsCmd.Flags().IntVar(&statsGiveUpFlag, "give-up", 3, "number of the failing attempts after which the student gave up")
+}
 
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// statsCmd.PersistentFlags().String("foo", "", "A help for foo")
+func executeStats(cmd *cobra.Command, args []string) {
+	config, err := app.GetAppConfig()
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	application, err := app.GetApplication(&config)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+	defer application.Close()
+
+	if len(args) == 0 {
+		args = []string{"*.json*"}
+	}
+
+	if statsAttemptsFlag {
+		executeAttemptsStats(&application, args)
+		return
+	}
+
+	snapshots, err := application.ParsedSnapshots(args)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	if statsJSONFlag {
+		summaries := make([]core.NotepadSummary, len(snapshots))
+		for i := range snapshots {
+			summaries[i] = snapshots[i].Summary
+		}
+		content, err := json.MarshalIndent(summaries, "", "  ")
+		if err != nil {
+			fmt.Printf("error: %v", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(content))
+		return
+	}
+
+	for i := range snapshots {
+		printNotepadSummary(&snapshots[i].Summary)
+		if statsChartFlag {
+			printNotepadCharts(snapshots[i].Students)
+		}
+	}
+}
+
+func executeAttemptsStats(application *app.IsStatApp, patterns []string) {
+	analyses, err := application.AttemptsStats(patterns, statsGiveUpFlag)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	if statsJSONFlag {
+		content, err := json.MarshalIndent(analyses, "", "  ")
+		if err != nil {
+			fmt.Printf("error: %v", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(content))
+		return
+	}
+
+	for i := range analyses {
+		printAttemptsAnalysis(&analyses[i])
+	}
+}
+
+func printAttemptsAnalysis(analysis *core.AttemptsAnalysis) {
+	fmt.Printf("Notepad: [%s] %s\n", analysis.Notepad, analysis.TimeStamp)
+	if analysis.Computable {
+		fmt.Printf("  Students: %4d  Passed (>= %g): %4d  Full (>= %g): %4d  Gave up (%d+ failing): %4d\n",
+			analysis.Students, analysis.PassPoints, analysis.Passed, analysis.FullPoints, analysis.Full,
+			analysis.GaveUpAfter, analysis.GaveUp)
+	} else {
+		fmt.Printf("  Students: %4d  Passed, full and gave up: not computable (no full points)\n", analysis.Students)
+	}
+	fmt.Printf("  %-7s %8s %8s %8s %8s %8s\n", "", "mean", "median", "stddev", "min", "max")
+	printValueSummary("to pass", &analysis.ToPass)
+	printValueSummary("to full", &analysis.ToFull)
+
+	fmt.Printf("\n  %7s %8s %8s %8s\n", "attempt", "students", "points", "gain")
+	for _, attempt := range analysis.PerAttempt {
+		fmt.Printf("  %7d %8d %8.2f %+8.2f\n", attempt.Attempt, attempt.Students, attempt.MeanPoints, attempt.MeanGain)
+	}
+
+	fmt.Println("\n  Attempts distribution:")
+	var bars []charts.Bar
+	for _, bucket := range analysis.Attempts {
+		bars = append(bars, charts.Bar{Label: bucket.Label(), Value: float64(bucket.Count)})
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(charts.HorizontalBars(bars, statsChartWidth), "\n"), "\n") {
+		fmt.Printf("    %s\n", line)
+	}
+	fmt.Println()
+}
+
+func printNotepadSummary(summary *core.NotepadSummary) {
+	fmt.Printf("Notepad: [%s] %s\n", summary.Name, summary.TimeStamp)
+	fmt.Printf("  Students: %4d  Submitted: %4d  Submissions: %5d  Final: %4d (%5.1f%%)\n",
+		summary.Students, summary.Submitted, summary.Submissions, summary.WithFinal, summary.FinalShare*100)
+	fmt.Printf("  %-7s %8s %8s %8s %8s %8s\n", "", "mean", "median", "stddev", "min", "max")
+	printValueSummary("points", &summary.Points)
+	printValueSummary("bonus", &summary.Bonus)
+}
+
+func printNotepadCharts(students []core.StudentInfo) {
+	fmt.Println("\n  Points of the final submissions:")
+	var bars []charts.Bar
+	for _, bucket := range core.NewHistogram(core.FinalPoints(students), 10) {
+		bars = append(bars, charts.Bar{Label: bucket.Label(), Value: float64(bucket.Count)})
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(charts.HorizontalBars(bars, statsChartWidth), "\n"), "\n") {
+		fmt.Printf("    %s\n", line)
+	}
+
+	timeline := core.NewSubmissionTimeline(students, time.Local)
+	if len(timeline) > 0 {
+		perDay := make([]float64, len(timeline))
+		max := 0
+		for i, point := range timeline {
+			perDay[i] = float64(point.Count)
+			if point.Count > max {
+				max = point.Count
+			}
+		}
+		fmt.Printf("\n  Submissions per day (%s .. %s, max %d):\n    %s\n",
+			timeline[0].Day.Format("2006-01-02"), timeline[len(timeline)-1].Day.Format("2006-01-02"), max, charts.Sparkline(perDay))
+	}
+
+	hours := core.NewSubmissionsPerHour(students, time.Local)
+	perHour := make([]float64, len(hours))
+	max := 0
+	for hour, count := range hours {
+		perHour[hour] = float64(count)
+		if count > max {
+			max = count
+		}
+	}
+	fmt.Printf("\n  Submissions per hour of day (max %d):\n    %s\n    %s\n\n", max, charts.Sparkline(perHour), hourAxis)
+}
 
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// statsCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+func printValueSummary(name string, value *core.ValueSummary) {
+	fmt.Printf("  %-7s %8.2f %8.2f %8.2f %8.2f %8.2f\n",
+		name, value.Mean, value.Median, value.StdDev, value.Min, value.Max)
 }
@@ -0,0 +1,115 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+	"github.com/pestanko/isstat/app"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncAllFlag   bool
+	syncMatchFlag []string
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync [NOTEPAD...]",
+	Short: "Fetch, parse, convert to CSV and summarize the notepads in one run",
+	Long: `Fetch, parse, convert to CSV and summarize the notepads in one run,
+all the results share the same snapshot timestamp.
+
+The pipeline continues past the failures of one notepad and prints
+the table of the steps that succeeded, were skipped or failed.
+Exit code is 0 when all the notepads succeeded, 2 when some of them failed
+and 1 when all of them failed or no notepads were provided or matched. For example:
+
+	isstat sync hw01 hw02
+	isstat sync --match 'hw*'`,
+	Run: executeSync,
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.Flags().BoolVar(&syncAllFlag, "all", false, "sync all the notepads of the course")
+	syncCmd.Flags().StringSliceVar(&syncMatchFlag, "match", nil, "sync the course notepads matching the glob pattern")
+}
+
+func executeSync(cmd *cobra.Command, args []string) {
+	config, err := app.GetAppConfig()
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	application, err := app.GetApplication(&config)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+	defer application.Close()
+
+	notepads, err := resolveNotepads(&application, args, syncAllFlag, syncMatchFlag)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	results, syncErr := application.Sync(notepads)
+
+	printSyncResults(results)
+
+	if code := syncExitCode(results, syncErr); code != 0 {
+		fmt.Printf("error: %v\n", syncErr)
+		_ = application.Close()
+		os.Exit(code)
+	}
+}
+
+func printSyncResults(results []app.SyncResult) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "NOTEPAD\tTIMESTAMP\tFETCH\tPARSE\tCSV\tSTATS\tSTUDENTS\tMEAN\tERROR")
+	for _, result := range results {
+		students, mean := "-", "-"
+		if result.Summary != nil {
+			students = fmt.Sprintf("%d", result.Summary.Students)
+			mean = fmt.Sprintf("%.2f", result.Summary.Points.Mean)
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			result.Notepad, result.TimeStamp, result.Fetch, result.Parse, result.CSV, result.Stats,
+			students, mean, result.Error)
+	}
+	_ = writer.Flush()
+}
+
+// syncExitCode - 0 for success, 2 for the partial failure, 1 when nothing succeeded (or there was nothing to sync)
+func syncExitCode(results []app.SyncResult, err error) int {
+	if err == nil {
+		return 0
+	}
+
+	for _, result := range results {
+		if !result.Failed() {
+			return 2
+		}
+	}
+	return 1
+}
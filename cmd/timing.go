@@ -0,0 +1,140 @@
+/*
+Copyright © 2020 NAME HERE <EMAIL ADDRESS>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pestanko/isstat/app"
+	"github.com/pestanko/isstat/charts"
+	"github.com/pestanko/isstat/core"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var timingJSONFlag bool
+
+// timingWeekdays - rows of the heatmap
+var timingWeekdays = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// timingCmd represents the timing command
+var timingCmd = &cobra.Command{
+	Use:   "timing [NOTEPAD_PATTERN...]",
+	Short: "Analyse the submission times relative to the deadlines",
+	Long: `Analyse the submission times of the latest parsed snapshots of the notepads
+against their deadlines configured in the config file:
+
+	notepads:
+	  hw01:
+	    deadline: "2020-03-08 23:59"
+
+For each notepad it prints how many hours before the deadline the students
+first submit and reach their final submission, the share of the late submissions
+and the day-of-week and hour-of-day heatmap (in the local time).
+The notepads without the deadline are skipped. For example:
+
+	isstat timing 'hw*'
+	isstat timing --json > timing.json`,
+	Run: executeTiming,
+}
+
+func init() {
+	rootCmd.AddCommand(timingCmd)
+
+	timingCmd.Flags().BoolVar(&timingJSONFlag, "json", false, "print the analyses as JSON")
+}
+
+func executeTiming(cmd *cobra.Command, args []string) {
+	config, err := app.GetAppConfig()
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	application, err := app.GetApplication(&config)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+	defer application.Close()
+
+	analyses, err := application.Timing(args)
+	if err != nil {
+		fmt.Printf("error: %v", err)
+		os.Exit(1)
+	}
+
+	if timingJSONFlag {
+		content, err := json.MarshalIndent(analyses, "", "  ")
+		if err != nil {
+			fmt.Printf("error: %v", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(content))
+		return
+	}
+
+	for i := range analyses {
+		printTimingAnalysis(&analyses[i])
+	}
+}
+
+func printTimingAnalysis(analysis *core.TimingAnalysis) {
+	fmt.Printf("Notepad: [%s] %s  Deadline: %s\n", analysis.Notepad, analysis.TimeStamp, analysis.Deadline.Local().Format(app.DeadlineLayout))
+	fmt.Printf("  Late submissions: %d of %d (%.1f%%)  Students late: %d  Final late: %d\n",
+		analysis.Lateness.Late, analysis.Lateness.Submissions, analysis.Lateness.LateShare()*100,
+		analysis.Lateness.LateStudents, analysis.Lateness.FinalLate)
+
+	fmt.Printf("\n  Hours before the deadline\n")
+	fmt.Printf("  %-7s %8s %8s %8s %8s %8s\n", "", "mean", "median", "stddev", "min", "max")
+	printValueSummary("first", &analysis.FirstHours)
+	printValueSummary("final", &analysis.FinalHours)
+
+	fmt.Println()
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(writer, "  \t")
+	for _, bucket := range analysis.First {
+		fmt.Fprintf(writer, "%s\t", bucket.Label)
+	}
+	fmt.Fprintln(writer)
+	for _, row := range []struct {
+		name    string
+		buckets []core.LeadTimeBucket
+	}{{"first", analysis.First}, {"final", analysis.Final}} {
+		fmt.Fprintf(writer, "  %s\t", row.name)
+		for _, bucket := range row.buckets {
+			fmt.Fprintf(writer, "%d\t", bucket.Count)
+		}
+		fmt.Fprintln(writer)
+	}
+	_ = writer.Flush()
+
+	rows := make([][]float64, len(analysis.Heatmap))
+	for day, hours := range analysis.Heatmap {
+		rows[day] = make([]float64, len(hours))
+		for hour, count := range hours {
+			rows[day][hour] = float64(count)
+		}
+	}
+
+	fmt.Printf("\n  Submissions by day and hour\n")
+	for day, line := range charts.Heatmap(rows) {
+		fmt.Printf("    %s |%s|\n", timingWeekdays[day], line)
+	}
+	fmt.Printf("         %s\n\n", hourAxis)
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"math"
+	"sort"
+)
+
+// AttemptPoints - points of the students' n-th attempts
+type AttemptPoints struct {
+	Attempt  int `json:"attempt"`
+	Students int `json:"students"`
+	// MeanPoints - mean points of the attempt
+	MeanPoints float64 `json:"mean_points"`
+	// MeanGain - mean points gained against the previous attempt (0 for the first one)
+	MeanGain float64 `json:"mean_gain"`
+}
+
+// AttemptsAnalysis - how many attempts the students of the notepad needed to succeed
+type AttemptsAnalysis struct {
+	Notepad    string  `json:"notepad"`
+	TimeStamp  string  `json:"timestamp"`
+	FullPoints float64 `json:"full_points"`
+	PassPoints float64 `json:"pass_points"`
+	// Computable - whether the passed and full students are computed, not without the full points
+	// (no configured full points and nobody has any points)
+	Computable bool `json:"computable"`
+	Students   int  `json:"students"`
+	// Attempts - distribution of the number of the students' attempts
+	Attempts []HistogramBucket `json:"attempts"`
+	Passed   int               `json:"passed"`
+	Full     int               `json:"full"`
+	// ToPass - attempts the passed students needed to reach the pass points
+	ToPass ValueSummary `json:"to_pass"`
+	// ToFull - attempts the students needed to reach the full points
+	ToFull     ValueSummary    `json:"to_full"`
+	PerAttempt []AttemptPoints `json:"per_attempt"`
+	// GaveUp - students who never reached the pass points in at least GaveUpAfter attempts
+	GaveUp      int `json:"gave_up"`
+	GaveUpAfter int `json:"gave_up_after"`
+}
+
+// NewAttemptsAnalysis - analyses the students' attempts ordered by the submission index
+//
+// The full points default to the maximum points of the notepad, the pass points to the half of the full points.
+// The zero full points can not tell who succeeded, so the passed, full and gave up students are not computed.
+func NewAttemptsAnalysis(notepad, timestamp string, students []StudentInfo, fullPoints, passPoints float64, giveUpAfter int) AttemptsAnalysis {
+	if fullPoints <= 0 {
+		fullPoints = maxPoints(students)
+	}
+	if passPoints <= 0 {
+		passPoints = fullPoints / 2
+	}
+
+	analysis := AttemptsAnalysis{
+		Notepad:     notepad,
+		TimeStamp:   timestamp,
+		FullPoints:  fullPoints,
+		PassPoints:  passPoints,
+		Computable:  fullPoints > 0,
+		Students:    len(students),
+		Attempts:    NewHistogram(Attempts(students), 10),
+		GaveUpAfter: giveUpAfter,
+	}
+
+	var toPass, toFull []float64
+	var points, gains [][]float64
+
+	for i := range students {
+		submissions := sortedSubmissions(students[i].Submissions)
+		passed, full := 0, 0
+
+		for n, submission := range submissions {
+			if n >= len(points) {
+				points = append(points, nil)
+				gains = append(gains, nil)
+			}
+			points[n] = append(points[n], submission.Points)
+			if n > 0 {
+				gains[n] = append(gains[n], submission.Points-submissions[n-1].Points)
+			}
+
+			if passed == 0 && submission.Points >= passPoints {
+				passed = n + 1
+			}
+			if full == 0 && submission.Points >= fullPoints {
+				full = n + 1
+			}
+		}
+
+		if !analysis.Computable {
+			continue
+		}
+		if passed > 0 {
+			analysis.Passed++
+			toPass = append(toPass, float64(passed))
+		} else if giveUpAfter > 0 && len(submissions) >= giveUpAfter {
+			analysis.GaveUp++
+		}
+		if full > 0 {
+			analysis.Full++
+			toFull = append(toFull, float64(full))
+		}
+	}
+
+	analysis.ToPass = NewValueSummary(toPass)
+	analysis.ToFull = NewValueSummary(toFull)
+
+	for n := range points {
+		attempt := AttemptPoints{
+			Attempt:    n + 1,
+			Students:   len(points[n]),
+			MeanPoints: NewValueSummary(points[n]).Mean,
+		}
+		if n > 0 {
+			attempt.MeanGain = NewValueSummary(gains[n]).Mean
+		}
+		analysis.PerAttempt = append(analysis.PerAttempt, attempt)
+	}
+
+	return analysis
+}
+
+func maxPoints(students []StudentInfo) float64 {
+	max := 0.0
+	for i := range students {
+		for _, submission := range students[i].Submissions {
+			max = math.Max(max, submission.Points)
+		}
+	}
+	return max
+}
+
+func sortedSubmissions(submissions []Submission) []Submission {
+	sorted := make([]Submission, len(submissions))
+	copy(sorted, submissions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Index < sorted[j].Index
+	})
+	return sorted
+}
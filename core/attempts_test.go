@@ -0,0 +1,73 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestNewAttemptsAnalysis(t *testing.T) {
+	// GIVEN
+	students := []StudentInfo{
+		// passes in the 2nd attempt, full in the 3rd one, submissions are not ordered
+		{Submissions: []Submission{{Index: 2, Points: 10}, {Index: 0, Points: 2}, {Index: 1, Points: 6}}},
+		// full in the 1st attempt
+		{Submissions: []Submission{{Index: 0, Points: 10}}},
+		// gave up after 3 failing attempts
+		{Submissions: []Submission{{Index: 0, Points: 1}, {Index: 1, Points: 2}, {Index: 2, Points: 1}}},
+		// failing, but not enough attempts to give up
+		{Submissions: []Submission{{Index: 0, Points: 0}}},
+	}
+
+	// WHEN
+	analysis := NewAttemptsAnalysis("hw01", "", students, 0, 0, 3)
+
+	// THEN
+	assertFloat(t, "full points", 10, analysis.FullPoints)
+	assertFloat(t, "pass points", 5, analysis.PassPoints)
+	if !analysis.Computable || analysis.Passed != 2 || analysis.Full != 2 || analysis.GaveUp != 1 {
+		t.Errorf("FAIL: Unexpected counts %+v", analysis)
+	}
+	assertFloat(t, "attempts to pass", 1.5, analysis.ToPass.Mean)
+	assertFloat(t, "attempts to full", 2, analysis.ToFull.Mean)
+
+	if len(analysis.PerAttempt) != 3 {
+		t.Fatalf("FAIL: Expected 3 attempts, got %+v", analysis.PerAttempt)
+	}
+	assertFloat(t, "1st attempt points", 13.0/4, analysis.PerAttempt[0].MeanPoints)
+	assertFloat(t, "2nd attempt gain", 2.5, analysis.PerAttempt[1].MeanGain)
+	assertFloat(t, "3rd attempt gain", 1.5, analysis.PerAttempt[2].MeanGain)
+	if analysis.PerAttempt[2].Students != 2 {
+		t.Errorf("FAIL: Two students made the 3rd attempt, got %d", analysis.PerAttempt[2].Students)
+	}
+}
+
+func TestNewAttemptsAnalysis_NoPoints(t *testing.T) {
+	// GIVEN
+	students := []StudentInfo{
+		{Submissions: []Submission{{Index: 0, Points: 0}}},
+		{Submissions: []Submission{{Index: 0, Points: 0}, {Index: 1, Points: 0}, {Index: 2, Points: 0}}},
+	}
+
+	// WHEN
+	analysis := NewAttemptsAnalysis("hw01", "", students, 0, 0, 3)
+
+	// THEN
+	if analysis.Computable || analysis.Passed != 0 || analysis.Full != 0 || analysis.GaveUp != 0 {
+		t.Errorf("FAIL: Zero full points should not be computable, got %+v", analysis)
+	}
+	if len(analysis.PerAttempt) != 3 {
+		t.Errorf("FAIL: Per attempt points should be still computed, got %+v", analysis.PerAttempt)
+	}
+}
+
+func TestNewAttemptsAnalysis_ConfiguredPoints(t *testing.T) {
+	// GIVEN
+	students := []StudentInfo{{Submissions: []Submission{{Index: 0, Points: 7}}}}
+
+	// WHEN
+	analysis := NewAttemptsAnalysis("hw01", "", students, 20, 7, 0)
+
+	// THEN
+	if analysis.Passed != 1 || analysis.Full != 0 || analysis.GaveUp != 0 {
+		t.Errorf("FAIL: Unexpected counts %+v", analysis)
+	}
+}
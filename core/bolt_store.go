@@ -0,0 +1,156 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+)
+
+// resultsBucket - bucket holding the result items keyed by their full names
+var resultsBucket = []byte("results")
+
+// BoltStore - stores all the result items in one embedded database file
+//
+// The database is opened for each operation, so the file is locked only while the operation runs
+// (shared by the reads, exclusive by the writes) and the long-running commands (serve, api)
+// do not block the other runs using the same file.
+type BoltStore struct {
+	File string
+}
+
+// boltTimeout - how long the operation waits for the database file locked by another one
+const boltTimeout = time.Minute
+
+// OpenBoltStore - creates the database file (and the results bucket) when it does not exist
+func OpenBoltStore(file string) (*BoltStore, error) {
+	if file == "" {
+		return nil, fmt.Errorf("bolt store requires the database file")
+	}
+
+	store := &BoltStore{File: file}
+	err := store.update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(resultsBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Put - stores the item's data
+func (store *BoltStore) Put(item *ResultItem) error {
+	return store.update(func(tx *bolt.Tx) error {
+		return tx.Bucket(resultsBucket).Put([]byte(item.GetFullName()), item.Data)
+	})
+}
+
+// Get - loads the item's data
+func (store *BoltStore) Get(item *ResultItem) (*ResultItem, error) {
+	err := store.view(func(tx *bolt.Tx) error {
+		data := tx.Bucket(resultsBucket).Get([]byte(item.GetFullName()))
+		if data == nil {
+			return fmt.Errorf("result not found: %s", item.GetFullName())
+		}
+		// the data are valid only inside the transaction
+		item.Data = append([]byte{}, data...)
+		return nil
+	})
+	if err != nil {
+		log.WithField("name", item.GetFullName()).WithError(err).Error("Unable to read a result")
+		return nil, err
+	}
+	return item, nil
+}
+
+// List - lists all the stored items
+func (store *BoltStore) List() ([]ResultItem, error) {
+	names, err := store.listNames()
+	if err != nil {
+		return nil, err
+	}
+	return listItems(names), nil
+}
+
+// Delete - removes the item
+func (store *BoltStore) Delete(item *ResultItem) error {
+	return store.update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(resultsBucket)
+		key := []byte(item.GetFullName())
+		if bucket.Get(key) == nil {
+			return fmt.Errorf("result not found: %s", item.GetFullName())
+		}
+		return bucket.Delete(key)
+	})
+}
+
+// Glob - names of the stored items matching the pattern
+func (store *BoltStore) Glob(pattern string) []string {
+	names, err := store.listNames()
+	if err != nil {
+		log.WithError(err).Warning("Unable to list the results")
+		return nil
+	}
+
+	matched, err := globNames(pattern, names)
+	if err != nil {
+		log.WithError(err).WithField("pattern", pattern).Warning("Glob error occurred")
+		return nil
+	}
+	return matched
+}
+
+// Location - the database file
+func (store *BoltStore) Location() string {
+	return store.File
+}
+
+// Close - nothing to release, the database is closed after each operation
+func (store *BoltStore) Close() error {
+	return nil
+}
+
+func (store *BoltStore) listNames() ([]string, error) {
+	var names []string
+	err := store.view(func(tx *bolt.Tx) error {
+		return tx.Bucket(resultsBucket).ForEach(func(key, _ []byte) error {
+			names = append(names, string(key))
+			return nil
+		})
+	})
+	return names, err
+}
+
+// view - runs the read-only transaction on the database opened with the shared lock
+func (store *BoltStore) view(fn func(tx *bolt.Tx) error) error {
+	db, err := store.open(true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.View(fn)
+}
+
+// update - runs the read-write transaction on the database opened with the exclusive lock
+func (store *BoltStore) update(fn func(tx *bolt.Tx) error) error {
+	db, err := store.open(false)
+	if err != nil {
+		return err
+	}
+	if err := db.Update(fn); err != nil {
+		_ = db.Close()
+		return err
+	}
+	return db.Close()
+}
+
+func (store *BoltStore) open(readOnly bool) (*bolt.DB, error) {
+	db, err := bolt.Open(store.File, 0644, &bolt.Options{Timeout: boltTimeout, ReadOnly: readOnly})
+	if err != nil {
+		log.WithField("file", store.File).WithError(err).Error("Unable to open the results database")
+		return nil, err
+	}
+	return db, nil
+}
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// Change event types
+const (
+	EventNewSubmission      = "new_submission"
+	EventPointsChanged      = "points_changed"
+	EventBecameFinal        = "became_final"
+	EventStudentAppeared    = "student_appeared"
+	EventStudentDisappeared = "student_disappeared"
+	EventEditedByOther      = "edited_by_other"
+)
+
+// ChangeEvent - one change between two consecutive snapshots of the notepad
+type ChangeEvent struct {
+	Type      string      `json:"type"`
+	Notepad   string      `json:"notepad"`
+	From      string      `json:"from"`
+	To        string      `json:"to"`
+	StudentID uuid.UUID   `json:"student_id"`
+	Previous  *Submission `json:"previous,omitempty"`
+	Current   *Submission `json:"current,omitempty"`
+}
+
+// ChangeDetector - detects the changes between the parsed snapshots of one notepad
+type ChangeDetector struct {
+	Notepad string
+}
+
+// IsEditedByOther - whether the entry edited by the account (UCO) was not written by the Kontr
+//
+// No Kontr accounts or unknown editor means the entry is not reported as edited by other.
+func IsEditedByOther(kontrAccounts []string, changedBy string) bool {
+	if len(kontrAccounts) == 0 || changedBy == "" {
+		return false
+	}
+
+	for _, account := range kontrAccounts {
+		if account == changedBy {
+			return false
+		}
+	}
+	return true
+}
+
+// Detect - compares the previous and the current snapshot
+//
+// The students are matched by their IDs, the submissions by their indexes.
+// The events are ordered by the student ID and the submission index.
+func (detector *ChangeDetector) Detect(from, to string, previous, current []StudentInfo) []ChangeEvent {
+	var events []ChangeEvent
+
+	newEvent := func(eventType string, id uuid.UUID) ChangeEvent {
+		return ChangeEvent{Type: eventType, Notepad: detector.Notepad, From: from, To: to, StudentID: id}
+	}
+
+	previousByID := make(map[uuid.UUID]*StudentInfo)
+	for i := range previous {
+		previousByID[previous[i].ID] = &previous[i]
+	}
+
+	currentIDs := make(map[uuid.UUID]bool)
+	for i := range current {
+		student := &current[i]
+		currentIDs[student.ID] = true
+
+		old, ok := previousByID[student.ID]
+		if !ok {
+			events = append(events, newEvent(EventStudentAppeared, student.ID))
+			old = &StudentInfo{ID: student.ID}
+		}
+
+		submissionEvents := detector.compareSubmissions(old, student, newEvent)
+		events = append(events, submissionEvents...)
+
+		if student.EditedByOther && (len(submissionEvents) > 0 || !old.EditedByOther) {
+			events = append(events, newEvent(EventEditedByOther, student.ID))
+		}
+	}
+
+	for i := range previous {
+		if !currentIDs[previous[i].ID] {
+			events = append(events, newEvent(EventStudentDisappeared, previous[i].ID))
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].StudentID.String() < events[j].StudentID.String()
+	})
+
+	return events
+}
+
+func (detector *ChangeDetector) compareSubmissions(old, student *StudentInfo, newEvent func(string, uuid.UUID) ChangeEvent) []ChangeEvent {
+	var events []ChangeEvent
+
+	oldByIndex := make(map[int]Submission)
+	for _, submission := range old.Submissions {
+		oldByIndex[submission.Index] = submission
+	}
+
+	submissions := make([]Submission, len(student.Submissions))
+	copy(submissions, student.Submissions)
+	sort.SliceStable(submissions, func(i, j int) bool {
+		return submissions[i].Index < submissions[j].Index
+	})
+
+	for i := range submissions {
+		submission := submissions[i]
+		oldSubmission, ok := oldByIndex[submission.Index]
+		if !ok {
+			event := newEvent(EventNewSubmission, student.ID)
+			event.Current = &submission
+			events = append(events, event)
+			continue
+		}
+
+		if oldSubmission.Points != submission.Points || oldSubmission.Bonus != submission.Bonus {
+			event := newEvent(EventPointsChanged, student.ID)
+			event.Previous = &oldSubmission
+			event.Current = &submission
+			events = append(events, event)
+		}
+
+		if !oldSubmission.Final && submission.Final {
+			event := newEvent(EventBecameFinal, student.ID)
+			event.Previous = &oldSubmission
+			event.Current = &submission
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
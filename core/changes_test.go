@@ -0,0 +1,87 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestChangeDetector_Detect(t *testing.T) {
+	// GIVEN
+	kept, appeared, gone := uuid.New(), uuid.New(), uuid.New()
+	previous := []StudentInfo{
+		{ID: kept, Submissions: []Submission{{Index: 1, Points: 1}, {Index: 2, Points: 2}}},
+		{ID: gone},
+	}
+	current := []StudentInfo{
+		{ID: kept, EditedByOther: true, Submissions: []Submission{{Index: 1, Points: 1}, {Index: 2, Points: 3, Final: true}, {Index: 3, Points: 3}}},
+		{ID: appeared, Submissions: []Submission{{Index: 1, Points: 5}}},
+	}
+	detector := ChangeDetector{Notepad: "hw01"}
+
+	// WHEN
+	events := detector.Detect("t1", "t2", previous, current)
+
+	// THEN
+	counts := make(map[string]int)
+	for _, event := range events {
+		counts[event.Type]++
+		if event.Notepad != "hw01" || event.From != "t1" || event.To != "t2" {
+			t.Errorf("FAIL: Unexpected event context: %v", event)
+		}
+		if event.Type == EventEditedByOther && event.StudentID != kept {
+			t.Errorf("FAIL: Unexpected edit event: %v", event)
+		}
+	}
+
+	expected := map[string]int{
+		EventNewSubmission:      2,
+		EventPointsChanged:      1,
+		EventBecameFinal:        1,
+		EventStudentAppeared:    1,
+		EventStudentDisappeared: 1,
+		EventEditedByOther:      1,
+	}
+	for eventType, count := range expected {
+		if counts[eventType] != count {
+			t.Errorf("FAIL: Found %d %s events, expected: %d", counts[eventType], eventType, count)
+		}
+	}
+}
+
+func TestChangeDetector_NoChanges(t *testing.T) {
+	// GIVEN
+	students := []StudentInfo{{ID: uuid.New(), EditedByOther: true, Submissions: []Submission{{Index: 1, Points: 1}}}}
+	detector := ChangeDetector{Notepad: "hw01"}
+
+	// WHEN
+	events := detector.Detect("t1", "t2", students, students)
+
+	// THEN
+	if len(events) != 0 {
+		t.Errorf("FAIL: Expected no events, found: %v", events)
+	}
+}
+
+func TestIsEditedByOther(t *testing.T) {
+	cases := []struct {
+		accounts  []string
+		changedBy string
+		expected  bool
+	}{
+		{[]string{"1000"}, "2000", true},
+		{[]string{"1000", "2000"}, "2000", false},
+		{[]string{"1000"}, "", false},
+		{nil, "2000", false},
+	}
+
+	for _, c := range cases {
+		// WHEN
+		edited := IsEditedByOther(c.accounts, c.changedBy)
+
+		// THEN
+		if edited != c.expected {
+			t.Errorf("FAIL: IsEditedByOther(%v, %q) = %v, expected: %v", c.accounts, c.changedBy, edited, c.expected)
+		}
+	}
+}
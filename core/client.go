@@ -1,14 +1,33 @@
 package core
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
+	"math/rand"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	// DefaultTimeout - default timeout of one request
+	DefaultTimeout = 30 * time.Second
+	// DefaultRetries - default number of retries of the failed (transient) request
+	DefaultRetries = 3
+	// DefaultBackoffBase - default delay before the first retry
+	DefaultBackoffBase = 500 * time.Millisecond
+	// DefaultBackoffMax - default maximal delay between retries
+	DefaultBackoffMax = 30 * time.Second
+	// DefaultRateLimit - default maximal number of requests per second to one host
+	DefaultRateLimit = 2.0
+)
+
 // NotepadContent - whole notepad content
 type NotepadContent struct {
 	StudentsContent []StudentContent `xml:"STUDENT" json:"students"`
@@ -21,6 +40,17 @@ type StudentContent struct {
 	ChangedBy string `xml:"ZMENIL" json:"changed_by"`
 }
 
+// NotepadList - list of the course notepads
+type NotepadList struct {
+	Notepads []NotepadInfo `xml:"BLOK" json:"notepads"`
+}
+
+// NotepadInfo - basic information about one course notepad
+type NotepadInfo struct {
+	Shortname string `xml:"ZKRATKA" json:"shortname"`
+	Title     string `xml:"JMENO" json:"title"`
+}
+
 // CourseClient - crawls the is muni notepads
 type CourseClient struct {
 	URL       string
@@ -28,6 +58,45 @@ type CourseClient struct {
 	FacultyID int
 	Course    string
 	DryRun    bool
+	// Timeout of one request (0 means no timeout)
+	Timeout time.Duration
+	// Retries - how many times the transient failure (network error, 429, 5xx) is retried
+	Retries int
+	// BackoffBase - delay before the first retry, doubled for each next retry
+	BackoffBase time.Duration
+	// BackoffMax - maximal delay between retries
+	BackoffMax time.Duration
+	// RateLimit - maximal number of requests per second to one host (0 means unlimited)
+	RateLimit float64
+}
+
+// StatusError - the server responded with unexpected status code
+type StatusError struct {
+	StatusCode int
+}
+
+func (err *StatusError) Error() string {
+	return fmt.Sprintf("Status error: %d", err.StatusCode)
+}
+
+// Transient - whether the request may succeed when retried
+func (err *StatusError) Transient() bool {
+	return err.StatusCode == http.StatusTooManyRequests || err.StatusCode >= 500
+}
+
+// APIError - error reported by the IS in the response body
+type APIError struct {
+	Message string
+}
+
+func (err *APIError) Error() string {
+	return fmt.Sprintf("IS error: %s", err.Message)
+}
+
+// WriteResult - result of writing the content to one student's notepad
+type WriteResult struct {
+	Uco string `json:"uco"`
+	Err error  `json:"-"`
 }
 
 // UnmarshalNotepadContent - unmarshal the notepad content
@@ -39,9 +108,29 @@ func UnmarshalNotepadContent(data []byte) (content NotepadContent, err error) {
 	return content, nil
 }
 
+// UnmarshalNotepadList - unmarshal the list of the course notepads
+func UnmarshalNotepadList(data []byte) (list NotepadList, err error) {
+	if err := xml.Unmarshal(data, &list); err != nil {
+		return list, err
+	}
+
+	return list, nil
+}
+
 // NewCourseClient - Creates a new course client
 func NewCourseClient(url string, token string, facultyID int, course string) CourseClient {
-	return CourseClient{URL: url, Token: token, FacultyID: facultyID, Course: course, DryRun: false}
+	return CourseClient{
+		URL:         url,
+		Token:       token,
+		FacultyID:   facultyID,
+		Course:      course,
+		DryRun:      false,
+		Timeout:     DefaultTimeout,
+		Retries:     DefaultRetries,
+		BackoffBase: DefaultBackoffBase,
+		BackoffMax:  DefaultBackoffMax,
+		RateLimit:   DefaultRateLimit,
+	}
 }
 
 //GetNotepadContent - Gets a notepad content
@@ -71,6 +160,59 @@ func (client *CourseClient) GetNotepadContentData(notepadCodename string) ([]byt
 	return data, nil
 }
 
+// ListNotepads - lists all the notepads of the course
+func (client *CourseClient) ListNotepads() ([]NotepadInfo, error) {
+	listURL := client.buildListURL()
+
+	log.WithField("url", listURL).Info("Listing the course notepads")
+
+	data, err := client.Fetch(listURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.DryRun {
+		return []NotepadInfo{}, nil
+	}
+
+	if err := checkAPIError(data); err != nil {
+		return nil, err
+	}
+
+	list, err := UnmarshalNotepadList(data)
+	if err != nil {
+		return nil, err
+	}
+	return list.Notepads, nil
+}
+
+// WriteStudentContent - writes (overwrites) the content of the student's notepad
+func (client *CourseClient) WriteStudentContent(notepadCodename, uco, content string) error {
+	log.WithField("notepad", notepadCodename).WithField("uco", uco).Info("Writing the student's notepad content")
+
+	data, err := client.Post(client.buildAPIURL(), client.buildWriteForm(notepadCodename, uco, content))
+	if err != nil {
+		return err
+	}
+
+	return checkAPIError(data)
+}
+
+// WriteNotepadContent - writes the content for each of the students, it does not stop on the first failure
+func (client *CourseClient) WriteNotepadContent(notepadCodename string, contents []StudentContent) []WriteResult {
+	results := make([]WriteResult, len(contents))
+
+	for i, student := range contents {
+		results[i] = WriteResult{Uco: student.Uco}
+		if err := client.WriteStudentContent(notepadCodename, student.Uco, student.Content); err != nil {
+			log.WithError(err).WithField("uco", student.Uco).Error("Unable to write the student's notepad content")
+			results[i].Err = err
+		}
+	}
+
+	return results
+}
+
 //Save the data to the XML file (caching)
 func (client *CourseClient) Save(data []byte, name string) error {
 
@@ -82,6 +224,9 @@ func (client *CourseClient) Save(data []byte, name string) error {
 }
 
 // Fetch - fetches XML data
+//
+// Transient failures are retried with the exponential backoff with jitter,
+// requests to one host are limited by the client's RateLimit.
 func (client *CourseClient) Fetch(url string) ([]byte, error) {
 	log.WithField("url", url).Debug("Fetching data")
 
@@ -89,20 +234,63 @@ func (client *CourseClient) Fetch(url string) ([]byte, error) {
 		return []byte{}, nil
 	}
 
-	resp, err := http.Get(url)
+	return client.retry(url, func() (*http.Response, error) {
+		httpClient := http.Client{Timeout: client.Timeout}
+		return httpClient.Get(url)
+	})
+}
+
+// Post - posts the form to the url, the same retry and rate limiting rules as for the Fetch apply
+func (client *CourseClient) Post(target string, form url.Values) ([]byte, error) {
+	log.WithField("url", target).Debug("Posting data")
+
+	if client.DryRun {
+		return []byte{}, nil
+	}
+
+	return client.retry(target, func() (*http.Response, error) {
+		httpClient := http.Client{Timeout: client.Timeout}
+		return httpClient.PostForm(target, form)
+	})
+}
+
+func (client *CourseClient) retry(url string, send func() (*http.Response, error)) ([]byte, error) {
+	limiter := getHostRateLimiter(url, client.RateLimit)
+
+	for attempt := 0; ; attempt++ {
+		limiter.Wait()
+
+		data, err := readResponse(send())
+		if err == nil {
+			return data, nil
+		}
+
+		if attempt >= client.Retries || !isTransientError(err) {
+			log.WithError(err).WithField("url", url).WithField("attempt", attempt+1).Error("Request failed")
+			return nil, err
+		}
+
+		delay := client.backoff(attempt)
+		log.WithError(err).WithFields(log.Fields{
+			"url":     url,
+			"attempt": attempt + 1,
+			"delay":   delay,
+		}).Warn("Request failed, retrying")
+		time.Sleep(delay)
+	}
+}
+
+func readResponse(resp *http.Response, err error) ([]byte, error) {
 	if err != nil {
-		log.WithError(err).WithField("url", url).Error("Fetch failed")
 		return nil, err
 	}
 
+	defer resp.Body.Close()
+
 	if resp.StatusCode != http.StatusOK {
-		msg := fmt.Sprintf("Status error: %d", resp.StatusCode)
-		log.Warn(msg)
-		return nil, fmt.Errorf(msg)
+		return nil, &StatusError{StatusCode: resp.StatusCode}
 	}
 
-	defer resp.Body.Close()
-
 	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("Read body: %v", err)
@@ -110,6 +298,73 @@ func (client *CourseClient) Fetch(url string) ([]byte, error) {
 	return data, nil
 }
 
+// backoff - exponential backoff with jitter for the attempt (starting from 0)
+//
+// The delay is randomly chosen from the upper half of the exponential delay.
+func (client *CourseClient) backoff(attempt int) time.Duration {
+	if client.BackoffBase <= 0 {
+		return 0
+	}
+
+	delay := client.BackoffBase << uint(attempt)
+	if delay <= 0 || (client.BackoffMax > 0 && delay > client.BackoffMax) {
+		delay = client.BackoffMax
+	}
+
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+func isTransientError(err error) bool {
+	if statusErr, ok := err.(*StatusError); ok {
+		return statusErr.Transient()
+	}
+	// network errors, timeouts and interrupted bodies
+	return true
+}
+
+// checkAPIError - looks for the <CHYBA> element the IS uses to report the failed operation
+func checkAPIError(data []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			// EOF or not an XML document - nothing to report
+			return nil
+		}
+
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "CHYBA" {
+			var message string
+			if err := decoder.DecodeElement(&message, &start); err != nil {
+				return &APIError{Message: "unknown error"}
+			}
+			return &APIError{Message: strings.TrimSpace(message)}
+		}
+	}
+}
+
+func (client *CourseClient) buildAPIURL() string {
+	return fmt.Sprintf("%s/export/pb_blok_api", client.URL)
+}
+
+func (client *CourseClient) buildWriteForm(notepadCodename, uco, content string) url.Values {
+	form := url.Values{}
+	form.Set("klic", client.Token)
+	form.Set("fakulta", strconv.Itoa(client.FacultyID))
+	form.Set("kod", client.Course)
+	form.Set("operace", "blok-pis-student-obsah")
+	form.Set("zkratka", notepadCodename)
+	form.Set("uco", uco)
+	form.Set("obsah", content)
+	form.Set("prepis", "a")
+	return form
+}
+
+func (client *CourseClient) buildListURL() string {
+	return fmt.Sprintf(
+		"%s/export/pb_blok_api?klic=%s;fakulta=%d;kod=%s;operace=bloky-seznam",
+		client.URL, client.Token, client.FacultyID, client.Course)
+}
+
 func (client *CourseClient) buildNotesURL(notepadCodename string) string {
 	return fmt.Sprintf(
 		"%s/export/pb_blok_api?klic=%s;fakulta=%d;kod=%s;operace=blok-dej-obsah;zkratka=%s",
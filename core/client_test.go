@@ -0,0 +1,224 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(url string) CourseClient {
+	client := NewCourseClient(url, "token", 1433, "PB071")
+	client.BackoffBase = time.Millisecond
+	client.BackoffMax = 5 * time.Millisecond
+	client.RateLimit = 0
+	return client
+}
+
+func newFailingServer(failures int32, status int) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write([]byte("<BLOKY_OBSAH></BLOKY_OBSAH>"))
+	}))
+	return server, &calls
+}
+
+func TestFetch_RetriesTransientErrors(t *testing.T) {
+	// GIVEN
+	server, calls := newFailingServer(2, http.StatusServiceUnavailable)
+	defer server.Close()
+	client := newTestClient(server.URL)
+
+	// WHEN
+	data, err := client.Fetch(server.URL)
+
+	// THEN
+	if err != nil {
+		t.Fatalf("FAIL: Found error: %v", err)
+	}
+	if string(data) != "<BLOKY_OBSAH></BLOKY_OBSAH>" {
+		t.Errorf("FAIL: Unexpected data: %q", string(data))
+	}
+	if *calls != 3 {
+		t.Errorf("FAIL: Server called %d times, expected: %d", *calls, 3)
+	}
+}
+
+func TestFetch_GivesUpAfterRetries(t *testing.T) {
+	// GIVEN
+	server, calls := newFailingServer(10, http.StatusBadGateway)
+	defer server.Close()
+	client := newTestClient(server.URL)
+	client.Retries = 2
+
+	// WHEN
+	_, err := client.Fetch(server.URL)
+
+	// THEN
+	statusErr, ok := err.(*StatusError)
+	if !ok || statusErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("FAIL: Expected status error 502, got: %v", err)
+	}
+	if *calls != 3 {
+		t.Errorf("FAIL: Server called %d times, expected: %d", *calls, 3)
+	}
+}
+
+func TestFetch_DoesNotRetryClientErrors(t *testing.T) {
+	// GIVEN
+	server, calls := newFailingServer(10, http.StatusForbidden)
+	defer server.Close()
+	client := newTestClient(server.URL)
+
+	// WHEN
+	_, err := client.Fetch(server.URL)
+
+	// THEN
+	if err == nil {
+		t.Error("FAIL: Expected error")
+	}
+	if *calls != 1 {
+		t.Errorf("FAIL: Server called %d times, expected: %d", *calls, 1)
+	}
+}
+
+func TestFetch_Timeout(t *testing.T) {
+	// GIVEN
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	client := newTestClient(server.URL)
+	client.Timeout = 50 * time.Millisecond
+
+	// WHEN
+	data, err := client.Fetch(server.URL)
+
+	// THEN
+	if err != nil {
+		t.Fatalf("FAIL: Found error: %v", err)
+	}
+	if string(data) != "ok" || atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("FAIL: Unexpected data %q after %d calls", string(data), calls)
+	}
+}
+
+func TestRateLimiter_SpacesRequests(t *testing.T) {
+	// GIVEN
+	limiter := NewRateLimiter(100)
+	start := time.Now()
+
+	// WHEN
+	for i := 0; i < 5; i++ {
+		limiter.Wait()
+	}
+
+	// THEN
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("FAIL: 5 requests at 100 rps took %v, expected at least 40ms", elapsed)
+	}
+}
+
+func TestRateLimiter_Unlimited(t *testing.T) {
+	// GIVEN
+	limiter := NewRateLimiter(0)
+
+	// WHEN
+	wait := limiter.Reserve()
+
+	// THEN
+	if wait != 0 {
+		t.Errorf("FAIL: Unlimited limiter should not wait, waits: %v", wait)
+	}
+}
+
+func TestWriteStudentContent(t *testing.T) {
+	// GIVEN
+	var form map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		form = map[string]string{}
+		for key := range r.PostForm {
+			form[key] = r.PostForm.Get(key)
+		}
+		_, _ = w.Write([]byte("<ZAPIS>OK</ZAPIS>"))
+	}))
+	defer server.Close()
+	client := newTestClient(server.URL)
+
+	// WHEN
+	err := client.WriteStudentContent("total", "123456", "points: 10")
+
+	// THEN
+	if err != nil {
+		t.Fatalf("FAIL: Found error: %v", err)
+	}
+	expected := map[string]string{
+		"operace": "blok-pis-student-obsah",
+		"zkratka": "total",
+		"uco":     "123456",
+		"obsah":   "points: 10",
+		"prepis":  "a",
+	}
+	for key, value := range expected {
+		if form[key] != value {
+			t.Errorf("FAIL: Form value %s is %q, expected: %q", key, form[key], value)
+		}
+	}
+}
+
+func TestWriteStudentContent_APIError(t *testing.T) {
+	// GIVEN
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<VYSLEDEK><CHYBA>Neznámý blok</CHYBA></VYSLEDEK>"))
+	}))
+	defer server.Close()
+	client := newTestClient(server.URL)
+
+	// WHEN
+	err := client.WriteStudentContent("total", "123456", "points: 10")
+
+	// THEN
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.Message != "Neznámý blok" {
+		t.Errorf("FAIL: Expected API error, got: %v", err)
+	}
+}
+
+func TestListNotepads(t *testing.T) {
+	// GIVEN
+	var operation string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		operation = r.URL.RawQuery
+		_, _ = w.Write([]byte(`<BLOKY_SEZNAM>
+ <BLOK><JMENO>Domácí úkol 1</JMENO><ZKRATKA>hw01</ZKRATKA></BLOK>
+ <BLOK><JMENO>Review 1</JMENO><ZKRATKA>review01</ZKRATKA></BLOK>
+</BLOKY_SEZNAM>`))
+	}))
+	defer server.Close()
+	client := newTestClient(server.URL)
+
+	// WHEN
+	notepads, err := client.ListNotepads()
+
+	// THEN
+	if err != nil {
+		t.Fatalf("FAIL: Found error: %v", err)
+	}
+	if !strings.Contains(operation, "operace=bloky-seznam") {
+		t.Errorf("FAIL: Unexpected query: %s", operation)
+	}
+	if len(notepads) != 2 || notepads[0].Shortname != "hw01" || notepads[1].Title != "Review 1" {
+		t.Errorf("FAIL: Unexpected notepads: %v", notepads)
+	}
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"os"
+	"time"
 
 	"github.com/gocarina/gocsv"
 	log "github.com/sirupsen/logrus"
@@ -17,6 +18,8 @@ type CSVStatistic struct {
 	Points    float64 `json:"points" csv:"points"`
 	Bonus     float64 `json:"bonus" csv:"bonus"`
 	Final     bool    `json:"final" csv:"final"`
+	// Submitted - the submission time the DateTime, Date and Time are formatted from
+	Submitted time.Time `json:"-" csv:"-"`
 }
 
 // WriteStatisticsToCSVFile - writes statistics to the CSV file
@@ -32,6 +35,11 @@ func WriteStatisticsToCSVFile(file string, statistics []CSVStatistic) error {
 	return gocsv.MarshalFile(statistics, csvFile)
 }
 
+// MarshalStatisticsToCSV - marshals statistics to the CSV content
+func MarshalStatisticsToCSV(statistics []CSVStatistic) ([]byte, error) {
+	return gocsv.MarshalBytes(statistics)
+}
+
 // ConvertSubmissionsToCSVStatistics - Converter
 func ConvertSubmissionsToCSVStatistics(students []StudentInfo) []CSVStatistic {
 	var stats []CSVStatistic
@@ -47,6 +55,7 @@ func ConvertSubmissionsToCSVStatistics(students []StudentInfo) []CSVStatistic {
 				Points:    submission.Points,
 				Final:     submission.Final,
 				Bonus:     submission.Bonus,
+				Submitted: submission.DateTime,
 			}
 			stats = append(stats, stat)
 		}
@@ -0,0 +1,250 @@
+package core
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// CSV quoting modes
+const (
+	// CSVQuoteMinimal - only the fields containing the delimiter, quotes or newlines are quoted
+	CSVQuoteMinimal = "minimal"
+	// CSVQuoteAll - all the fields are quoted
+	CSVQuoteAll = "all"
+)
+
+// CSVColumns - the columns of the CSV statistics in the default order
+var CSVColumns = []string{"student_id", "index", "datetime", "date", "time", "points", "bonus", "final"}
+
+// utf8BOM - byte order mark telling Excel the file is UTF-8
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// CSVFormat - shape of the exported CSV statistics, the zero value writes the default CSV
+type CSVFormat struct {
+	// Columns - selected columns in the order (see CSVColumns), all by default
+	Columns []string `json:"columns" yaml:"columns" mapstructure:"columns"`
+	// Headers - header labels keyed by the column, the column name by default
+	Headers map[string]string `json:"headers" yaml:"headers" mapstructure:"headers"`
+	// Delimiter - one character field delimiter, "," by default
+	Delimiter string `json:"delimiter" yaml:"delimiter" mapstructure:"delimiter"`
+	// Decimal - decimal separator of the points, "." or ","
+	Decimal string `json:"decimal" yaml:"decimal" mapstructure:"decimal"`
+	// DateTimeFormat, DateFormat, TimeFormat - Go layouts of the submission time columns
+	DateTimeFormat string `json:"datetime_format" yaml:"datetime_format" mapstructure:"datetime_format"`
+	DateFormat     string `json:"date_format" yaml:"date_format" mapstructure:"date_format"`
+	TimeFormat     string `json:"time_format" yaml:"time_format" mapstructure:"time_format"`
+	// Quote - quoting mode, minimal or all
+	Quote string `json:"quote" yaml:"quote" mapstructure:"quote"`
+	// BOM - writes the UTF-8 byte order mark first
+	BOM bool `json:"bom" yaml:"bom" mapstructure:"bom"`
+}
+
+// Merge - the format with the fields set in the override replacing the ones of the format
+//
+// The BOM is written when enabled in any of them.
+func (format CSVFormat) Merge(override CSVFormat) CSVFormat {
+	if len(override.Columns) > 0 {
+		format.Columns = override.Columns
+	}
+	if len(override.Headers) > 0 {
+		headers := make(map[string]string, len(format.Headers)+len(override.Headers))
+		for column, header := range format.Headers {
+			headers[column] = header
+		}
+		for column, header := range override.Headers {
+			headers[column] = header
+		}
+		format.Headers = headers
+	}
+	for _, field := range []struct{ value, override *string }{
+		{&format.Delimiter, &override.Delimiter},
+		{&format.Decimal, &override.Decimal},
+		{&format.DateTimeFormat, &override.DateTimeFormat},
+		{&format.DateFormat, &override.DateFormat},
+		{&format.TimeFormat, &override.TimeFormat},
+		{&format.Quote, &override.Quote},
+	} {
+		if *field.override != "" {
+			*field.value = *field.override
+		}
+	}
+	format.BOM = format.BOM || override.BOM
+	return format
+}
+
+// Validate - checks the columns, the headers, the delimiter, the decimal separator and the quoting
+func (format *CSVFormat) Validate() error {
+	known := make(map[string]bool, len(CSVColumns))
+	for _, column := range CSVColumns {
+		known[column] = true
+	}
+	for _, column := range format.Columns {
+		if !known[column] {
+			return fmt.Errorf("unknown CSV column '%s', available: %s", column, strings.Join(CSVColumns, ", "))
+		}
+	}
+	for column := range format.Headers {
+		if !known[column] {
+			return fmt.Errorf("header of the unknown CSV column '%s'", column)
+		}
+	}
+	return format.validateLayout()
+}
+
+// validateLayout - checks the delimiter, the decimal separator and the quoting
+func (format *CSVFormat) validateLayout() error {
+	if format.Delimiter != "" {
+		delimiter, size := utf8.DecodeRuneInString(format.Delimiter)
+		if size != len(format.Delimiter) || delimiter == '"' || delimiter == '\r' || delimiter == '\n' {
+			return fmt.Errorf("invalid CSV delimiter %q", format.Delimiter)
+		}
+	}
+
+	switch format.Decimal {
+	case "", ".", ",":
+	default:
+		return fmt.Errorf("invalid decimal separator %q", format.Decimal)
+	}
+
+	switch format.Quote {
+	case "", CSVQuoteMinimal, CSVQuoteAll:
+	default:
+		return fmt.Errorf("unknown CSV quoting '%s'", format.Quote)
+	}
+	return nil
+}
+
+// Marshal - marshals the statistics to the CSV content of the format
+func (format *CSVFormat) Marshal(statistics []CSVStatistic) ([]byte, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
+	columns := format.Columns
+	if len(columns) == 0 {
+		columns = CSVColumns
+	}
+
+	records := make([][]string, 0, len(statistics)+1)
+	records = append(records, format.header(columns))
+	for i := range statistics {
+		record := make([]string, len(columns))
+		for j, column := range columns {
+			record[j] = format.field(&statistics[i], column)
+		}
+		records = append(records, record)
+	}
+	return format.write(records)
+}
+
+// MarshalWide - marshals the wide table to the CSV content of the format
+//
+// The columns selection and the date formats do not apply, the missing points are empty.
+func (format *CSVFormat) MarshalWide(table *WideTable) ([]byte, error) {
+	if err := format.validateLayout(); err != nil {
+		return nil, err
+	}
+
+	records := make([][]string, 0, len(table.Rows)+1)
+	records = append(records, format.header(append(append([]string{}, table.KeyColumns...), table.PointColumns...)))
+	for _, row := range table.Rows {
+		record := append([]string{}, row.Keys...)
+		for _, points := range row.Points {
+			cell := ""
+			if !math.IsNaN(points) {
+				cell = format.formatFloat(points)
+			}
+			record = append(record, cell)
+		}
+		records = append(records, record)
+	}
+	return format.write(records)
+}
+
+// header - labels of the columns
+func (format *CSVFormat) header(columns []string) []string {
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column
+		if label, ok := format.Headers[column]; ok {
+			header[i] = label
+		}
+	}
+	return header
+}
+
+// write - writes the records with the delimiter, the quoting and the BOM of the format
+func (format *CSVFormat) write(records [][]string) ([]byte, error) {
+	var buffer bytes.Buffer
+	if format.BOM {
+		buffer.Write(utf8BOM)
+	}
+
+	delimiter := ','
+	if format.Delimiter != "" {
+		delimiter, _ = utf8.DecodeRuneInString(format.Delimiter)
+	}
+
+	if format.Quote == CSVQuoteAll {
+		for _, record := range records {
+			for i, field := range record {
+				if i > 0 {
+					buffer.WriteRune(delimiter)
+				}
+				buffer.WriteString(`"` + strings.ReplaceAll(field, `"`, `""`) + `"`)
+			}
+			buffer.WriteByte('\n')
+		}
+		return buffer.Bytes(), nil
+	}
+
+	writer := csv.NewWriter(&buffer)
+	writer.Comma = delimiter
+	if err := writer.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (format *CSVFormat) field(statistic *CSVStatistic, column string) string {
+	switch column {
+	case "student_id":
+		return statistic.StudentID
+	case "index":
+		return strconv.Itoa(statistic.Index)
+	case "datetime":
+		return format.formatTime(statistic, format.DateTimeFormat, statistic.DateTime)
+	case "date":
+		return format.formatTime(statistic, format.DateFormat, statistic.Date)
+	case "time":
+		return format.formatTime(statistic, format.TimeFormat, statistic.Time)
+	case "points":
+		return format.formatFloat(statistic.Points)
+	case "bonus":
+		return format.formatFloat(statistic.Bonus)
+	case "final":
+		return strconv.FormatBool(statistic.Final)
+	}
+	return ""
+}
+
+// formatTime - the submission time in the layout, the default formatted value without the layout
+func (format *CSVFormat) formatTime(statistic *CSVStatistic, layout, value string) string {
+	if layout == "" {
+		return value
+	}
+	return statistic.Submitted.Format(layout)
+}
+
+func (format *CSVFormat) formatFloat(value float64) string {
+	formatted := strconv.FormatFloat(value, 'f', -1, 64)
+	if format.Decimal == "," {
+		return strings.Replace(formatted, ".", ",", 1)
+	}
+	return formatted
+}
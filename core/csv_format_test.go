@@ -0,0 +1,139 @@
+package core
+
+import (
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func csvFormatStudents() []StudentInfo {
+	submitted := time.Date(2020, 3, 8, 21, 30, 0, 0, time.UTC)
+	return []StudentInfo{{
+		ID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+		Submissions: []Submission{
+			{DateTime: submitted, Index: 0, Points: 7.5, Bonus: 0.25},
+			{DateTime: submitted.Add(time.Hour), Index: 1, Points: 10, Final: true},
+		},
+	}}
+}
+
+func TestCSVFormat_DefaultMatchesGoCSV(t *testing.T) {
+	// GIVEN
+	statistics := ConvertSubmissionsToCSVStatistics(csvFormatStudents())
+	expected, err := MarshalStatisticsToCSV(statistics)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	format := CSVFormat{}
+	provided, err := format.Marshal(statistics)
+
+	// THEN
+	if err != nil || string(provided) != string(expected) {
+		t.Errorf("FAIL: Expected %q, got %q (%v)", expected, provided, err)
+	}
+}
+
+func TestCSVFormat_Marshal(t *testing.T) {
+	// GIVEN
+	statistics := ConvertSubmissionsToCSVStatistics(csvFormatStudents())
+	format := CSVFormat{
+		Columns:        []string{"datetime", "points", "final"},
+		Headers:        map[string]string{"datetime": "Odevzdáno", "points": "Body"},
+		Delimiter:      ";",
+		Decimal:        ",",
+		DateTimeFormat: "02.01.2006 15:04",
+		Quote:          CSVQuoteAll,
+		BOM:            true,
+	}
+
+	// WHEN
+	content, err := format.Marshal(statistics)
+
+	// THEN
+	expected := "\xEF\xBB\xBF" + `"Odevzdáno";"Body";"final"` + "\n" +
+		`"08.03.2020 21:30";"7,5";"false"` + "\n" +
+		`"08.03.2020 22:30";"10";"true"` + "\n"
+	if err != nil || string(content) != expected {
+		t.Errorf("FAIL: Expected %q, got %q (%v)", expected, content, err)
+	}
+}
+
+func TestCSVFormat_DecimalCommaQuotedWithCommaDelimiter(t *testing.T) {
+	// GIVEN
+	statistics := ConvertSubmissionsToCSVStatistics(csvFormatStudents())
+	format := CSVFormat{Columns: []string{"index", "bonus"}, Decimal: ","}
+
+	// WHEN
+	content, err := format.Marshal(statistics)
+
+	// THEN
+	expected := "index,bonus\n0,\"0,25\"\n1,0\n"
+	if err != nil || string(content) != expected {
+		t.Errorf("FAIL: Expected %q, got %q (%v)", expected, content, err)
+	}
+}
+
+func TestCSVFormat_QuoteAllEscapesDelimiterAndNewline(t *testing.T) {
+	// GIVEN
+	table := WideTable{
+		KeyColumns:   []string{"student;id"},
+		PointColumns: []string{"hw01"},
+		Rows:         []WideRow{{Keys: []string{"first;\nsecond \"line\""}, Points: []float64{1.5}}},
+	}
+	format := CSVFormat{Delimiter: ";", Quote: CSVQuoteAll}
+
+	// WHEN
+	content, err := format.MarshalWide(&table)
+
+	// THEN
+	expected := `"student;id";"hw01"` + "\n" + `"first;` + "\n" + `second ""line""";"1.5"` + "\n"
+	if err != nil || string(content) != expected {
+		t.Fatalf("FAIL: Expected %q, got %q (%v)", expected, content, err)
+	}
+	reader := csv.NewReader(strings.NewReader(string(content)))
+	reader.Comma = ';'
+	records, err := reader.ReadAll()
+	if err != nil || !reflect.DeepEqual(records, [][]string{{"student;id", "hw01"}, {"first;\nsecond \"line\"", "1.5"}}) {
+		t.Errorf("FAIL: Unexpected records read back: %q (%v)", records, err)
+	}
+}
+
+func TestCSVFormat_Validate(t *testing.T) {
+	invalid := []CSVFormat{
+		{Columns: []string{"uco"}},
+		{Headers: map[string]string{"uco": "UCO"}},
+		{Delimiter: ";;"},
+		{Delimiter: `"`},
+		{Decimal: "'"},
+		{Quote: "none"},
+	}
+
+	for i := range invalid {
+		if err := invalid[i].Validate(); err == nil {
+			t.Errorf("FAIL: Expected the format %+v to be invalid", invalid[i])
+		}
+	}
+}
+
+func TestCSVFormat_Merge(t *testing.T) {
+	// GIVEN
+	global := CSVFormat{Delimiter: ";", Decimal: ",", Headers: map[string]string{"points": "Body"}}
+	notepad := CSVFormat{Columns: []string{"points"}, Headers: map[string]string{"bonus": "Bonus"}, BOM: true}
+
+	// WHEN
+	merged := global.Merge(notepad)
+
+	// THEN
+	if merged.Delimiter != ";" || merged.Decimal != "," || len(merged.Columns) != 1 || !merged.BOM {
+		t.Errorf("FAIL: Unexpected merged format %+v", merged)
+	}
+	if merged.Headers["points"] != "Body" || merged.Headers["bonus"] != "Bonus" || len(global.Headers) != 1 {
+		t.Errorf("FAIL: Expected the headers merged without changing the global ones, got %+v", merged.Headers)
+	}
+}
@@ -0,0 +1,31 @@
+package core
+
+import (
+	"os"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// FileLock - exclusive inter-process lock held on a lock file
+type FileLock struct {
+	file *os.File
+	path string
+}
+
+// LockFile - blocks until the exclusive lock on the file is acquired, the file is created if needed
+func LockFile(path string) (*FileLock, error) {
+	log.WithField("path", path).Debug("Acquiring the file lock")
+
+	lock, err := lockFile(path)
+	if err != nil {
+		log.WithField("path", path).WithError(err).Error("Unable to acquire the file lock")
+		return nil, err
+	}
+	return lock, nil
+}
+
+// Unlock - releases the lock
+func (lock *FileLock) Unlock() error {
+	log.WithField("path", lock.path).Debug("Releasing the file lock")
+	return lock.unlock()
+}
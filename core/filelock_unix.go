@@ -0,0 +1,31 @@
+//go:build !windows
+// +build !windows
+
+package core
+
+import (
+	"os"
+	"syscall"
+)
+
+func lockFile(path string) (*FileLock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return &FileLock{file: file, path: path}, nil
+}
+
+func (lock *FileLock) unlock() error {
+	if err := syscall.Flock(int(lock.file.Fd()), syscall.LOCK_UN); err != nil {
+		_ = lock.file.Close()
+		return err
+	}
+	return lock.file.Close()
+}
@@ -0,0 +1,28 @@
+//go:build windows
+// +build windows
+
+package core
+
+import (
+	"os"
+	"time"
+)
+
+// lockFile - the lock file is created exclusively, the lock is held while the file exists
+func lockFile(path string) (*FileLock, error) {
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_RDWR, 0600)
+		if err == nil {
+			return &FileLock{file: file, path: path}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func (lock *FileLock) unlock() error {
+	_ = lock.file.Close()
+	return os.Remove(lock.path)
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// FilesystemStore - stores each result item as the file in the results directory
+type FilesystemStore struct {
+	Dir string
+}
+
+// NewFilesystemStore - creates a new store in the directory (default is the working directory)
+func NewFilesystemStore(dir string) *FilesystemStore {
+	var err error
+	if dir == "" {
+		dir, err = os.Getwd()
+		if err != nil {
+			log.WithError(err).Warning("Unable to get current working directory")
+		}
+	}
+
+	return &FilesystemStore{Dir: dir}
+}
+
+// Put - writes the item's data to the file
+func (store *FilesystemStore) Put(item *ResultItem) error {
+	return WriteFileAtomic(store.GetPath(item), item.Data, 0644)
+}
+
+// Get - reads the item's data from the file
+func (store *FilesystemStore) Get(item *ResultItem) (*ResultItem, error) {
+	fullPath := store.GetPath(item)
+
+	data, err := ioutil.ReadFile(fullPath)
+	if err != nil {
+		log.WithField("fullPath", fullPath).WithError(err).Error("Unable to read a file")
+		return nil, err
+	}
+
+	item.Data = data
+	return item, nil
+}
+
+// List - lists the result items in the directory
+func (store *FilesystemStore) List() ([]ResultItem, error) {
+	names, err := store.listNames()
+	if err != nil {
+		return nil, err
+	}
+	return listItems(names), nil
+}
+
+// Delete - removes the item's file
+func (store *FilesystemStore) Delete(item *ResultItem) error {
+	return os.Remove(store.GetPath(item))
+}
+
+// Glob - names of the files in the directory matching the pattern
+func (store *FilesystemStore) Glob(pattern string) []string {
+	var filenames []string
+
+	fpath := path.Join(store.Dir, pattern)
+	log.WithField("pattern", pattern).WithField("patternPath", fpath).Debug("Globing pattern")
+	files, err := filepath.Glob(fpath)
+
+	if err != nil {
+		log.WithError(err).WithField("pattern", pattern).Warning("Glob error occurred")
+		return filenames
+	}
+
+	log.WithField("files", files).Debug("Glob found files")
+
+	for _, file := range files {
+		name := filepath.Base(file)
+		if isTemporaryFile(name) {
+			continue
+		}
+		filenames = append(filenames, name)
+	}
+
+	return filenames
+}
+
+// Location - the results directory
+func (store *FilesystemStore) Location() string {
+	return store.Dir
+}
+
+// Close - nothing to release
+func (store *FilesystemStore) Close() error {
+	return nil
+}
+
+// GetPath - gets a full result path
+func (store *FilesystemStore) GetPath(item *ResultItem) string {
+	return path.Join(store.Dir, item.GetFullName())
+}
+
+func (store *FilesystemStore) listNames() ([]string, error) {
+	files, err := ioutil.ReadDir(store.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, f := range files {
+		if f.IsDir() || isTemporaryFile(f.Name()) {
+			continue
+		}
+		names = append(names, f.Name())
+	}
+	return names, nil
+}
@@ -0,0 +1,339 @@
+package core
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path"
+	"sort"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v2"
+)
+
+// Bonus modes of the gradebook
+const (
+	// BonusAdd - the bonus is added to the points, the score may exceed the max points
+	BonusAdd = "add"
+	// BonusCap - the bonus is added to the points, the score is capped at the max points
+	BonusCap = "cap"
+	// BonusIgnore - the bonus is not counted
+	BonusIgnore = "ignore"
+)
+
+// Grading rule types
+const (
+	// RuleBest - only the Count best scores of the matching notepads are counted
+	RuleBest = "best"
+	// RuleMin - each of the matching notepads needs at least the Share of its max points, the grade is failing otherwise
+	RuleMin = "min"
+)
+
+// DefaultGrades - the grade thresholds (percent of the total) used when none are configured
+var DefaultGrades = []GradeThreshold{
+	{Grade: "A", Min: 90},
+	{Grade: "B", Min: 80},
+	{Grade: "C", Min: 70},
+	{Grade: "D", Min: 60},
+	{Grade: "E", Min: 50},
+	{Grade: "F", Min: 0},
+}
+
+// GradebookNotepad - notepad counted in the gradebook
+type GradebookNotepad struct {
+	Name string `json:"name" yaml:"name" mapstructure:"name"`
+	// Weight - weight of the notepad's score share in the total (default 1)
+	Weight float64 `json:"weight" yaml:"weight" mapstructure:"weight"`
+	// MaxPoints - points of the full score (default is the max points in the notepad)
+	MaxPoints float64 `json:"max_points" yaml:"max_points" mapstructure:"max_points"`
+}
+
+// GradingRule - rule applied to the notepads matching any of the glob patterns (all notepads when empty)
+type GradingRule struct {
+	Type     string   `json:"type" yaml:"type" mapstructure:"type"`
+	Notepads []string `json:"notepads" yaml:"notepads" mapstructure:"notepads"`
+	// Count - number of the best scores counted by the "best" rule
+	Count int `json:"count" yaml:"count" mapstructure:"count"`
+	// Share - minimum share of the max points required by the "min" rule (0.5 = 50 %)
+	Share float64 `json:"share" yaml:"share" mapstructure:"share"`
+}
+
+// GradeThreshold - minimum total percentage of the grade
+type GradeThreshold struct {
+	Grade string  `json:"grade" yaml:"grade" mapstructure:"grade"`
+	Min   float64 `json:"min" yaml:"min" mapstructure:"min"`
+}
+
+// Gradebook - grading rules joining the notepads
+//
+// The student's score of the notepad is the points of the final submission (plus the bonus by the Bonus mode),
+// the total is the weighted mean of the counted notepads' score shares in percent.
+type Gradebook struct {
+	Notepads []GradebookNotepad `json:"notepads" yaml:"notepads" mapstructure:"notepads"`
+	Bonus    string             `json:"bonus" yaml:"bonus" mapstructure:"bonus"`
+	Rules    []GradingRule      `json:"rules" yaml:"rules" mapstructure:"rules"`
+	Grades   []GradeThreshold   `json:"grades" yaml:"grades" mapstructure:"grades"`
+}
+
+// NotepadScore - student's score of one notepad
+type NotepadScore struct {
+	Points float64 `json:"points"`
+	Bonus  float64 `json:"bonus"`
+	Score  float64 `json:"score"`
+	// Share - share of the max points
+	Share float64 `json:"share"`
+	// Counted - whether the score is counted in the total (see the "best" rule)
+	Counted bool `json:"counted"`
+	// Missing - the student has no final submission of the notepad
+	Missing bool `json:"missing"`
+}
+
+// StudentGrade - student's scores, total and grade
+type StudentGrade struct {
+	StudentID uuid.UUID               `json:"student_id"`
+	Scores    map[string]NotepadScore `json:"scores"`
+	// Total - weighted total in percent
+	Total float64 `json:"total"`
+	Grade string  `json:"grade"`
+	// Failed - the violated rules, the grade is the failing one when not empty
+	Failed []string `json:"failed,omitempty"`
+}
+
+// LoadGradebook - loads the gradebook from the YAML file
+func LoadGradebook(file string) (Gradebook, error) {
+	var gradebook Gradebook
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return gradebook, err
+	}
+
+	if err := yaml.UnmarshalStrict(content, &gradebook); err != nil {
+		return gradebook, fmt.Errorf("invalid gradebook '%s': %v", file, err)
+	}
+	return gradebook, gradebook.Validate()
+}
+
+// Validate - checks the gradebook's notepads, rules and grades
+func (gradebook *Gradebook) Validate() error {
+	if len(gradebook.Notepads) == 0 {
+		return fmt.Errorf("gradebook has no notepads")
+	}
+
+	names := make(map[string]bool)
+	for _, notepad := range gradebook.Notepads {
+		if notepad.Name == "" {
+			return fmt.Errorf("gradebook notepad without the name")
+		}
+		if names[notepad.Name] {
+			return fmt.Errorf("duplicate gradebook notepad '%s'", notepad.Name)
+		}
+		if notepad.Weight < 0 || notepad.MaxPoints < 0 {
+			return fmt.Errorf("negative weight or max points of the notepad '%s'", notepad.Name)
+		}
+		names[notepad.Name] = true
+	}
+
+	switch gradebook.Bonus {
+	case "", BonusAdd, BonusCap, BonusIgnore:
+	default:
+		return fmt.Errorf("unknown bonus mode '%s'", gradebook.Bonus)
+	}
+
+	for _, rule := range gradebook.Rules {
+		for _, pattern := range rule.Notepads {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid notepad pattern '%s': %v", pattern, err)
+			}
+		}
+
+		switch rule.Type {
+		case RuleBest:
+			if rule.Count <= 0 {
+				return fmt.Errorf("the best rule needs the positive count")
+			}
+		case RuleMin:
+			if rule.Share <= 0 || rule.Share > 1 {
+				return fmt.Errorf("the min rule needs the share in (0, 1]")
+			}
+		default:
+			return fmt.Errorf("unknown grading rule '%s'", rule.Type)
+		}
+	}
+
+	return nil
+}
+
+// Grade - computes the students' totals and grades from the parsed notepads (keyed by the notepad name)
+//
+// The students are joined by their IDs, the student missing in the notepad has zero score.
+// The grades are ordered by the total, the best first.
+func (gradebook *Gradebook) Grade(notepads map[string][]StudentInfo) ([]StudentGrade, error) {
+	if err := gradebook.Validate(); err != nil {
+		return nil, err
+	}
+
+	configs := gradebook.notepadsWithDefaults(notepads)
+	thresholds := gradebook.thresholds()
+
+	finals := make(map[string]map[uuid.UUID]Submission)
+	var ids []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, notepad := range configs {
+		finals[notepad.Name] = make(map[uuid.UUID]Submission)
+		for i := range notepads[notepad.Name] {
+			student := &notepads[notepad.Name][i]
+			if !seen[student.ID] {
+				seen[student.ID] = true
+				ids = append(ids, student.ID)
+			}
+			if submission, ok := student.FinalSubmission(); ok {
+				finals[notepad.Name][student.ID] = submission
+			}
+		}
+	}
+
+	var grades []StudentGrade
+	for _, id := range ids {
+		grade := StudentGrade{StudentID: id, Scores: make(map[string]NotepadScore)}
+
+		for _, notepad := range configs {
+			submission, ok := finals[notepad.Name][id]
+			score := gradebook.score(submission, notepad.MaxPoints)
+			score.Missing = !ok
+			grade.Scores[notepad.Name] = score
+		}
+
+		for _, rule := range gradebook.Rules {
+			gradebook.applyRule(&rule, configs, &grade)
+		}
+
+		var weighted, weights float64
+		for _, notepad := range configs {
+			if score := grade.Scores[notepad.Name]; score.Counted {
+				weighted += notepad.Weight * score.Share
+				weights += notepad.Weight
+			}
+		}
+		if weights > 0 {
+			grade.Total = weighted / weights * 100
+		}
+
+		grade.Grade = thresholds[len(thresholds)-1].Grade
+		if len(grade.Failed) == 0 {
+			for _, threshold := range thresholds {
+				if grade.Total >= threshold.Min {
+					grade.Grade = threshold.Grade
+					break
+				}
+			}
+		}
+
+		grades = append(grades, grade)
+	}
+
+	sort.SliceStable(grades, func(i, j int) bool {
+		if grades[i].Total != grades[j].Total {
+			return grades[i].Total > grades[j].Total
+		}
+		return grades[i].StudentID.String() < grades[j].StudentID.String()
+	})
+	return grades, nil
+}
+
+// NotepadNames - names of the gradebook's notepads in the configured order
+func (gradebook *Gradebook) NotepadNames() []string {
+	names := make([]string, len(gradebook.Notepads))
+	for i, notepad := range gradebook.Notepads {
+		names[i] = notepad.Name
+	}
+	return names
+}
+
+func (gradebook *Gradebook) notepadsWithDefaults(notepads map[string][]StudentInfo) []GradebookNotepad {
+	configs := make([]GradebookNotepad, len(gradebook.Notepads))
+	for i, notepad := range gradebook.Notepads {
+		if notepad.Weight == 0 {
+			notepad.Weight = 1
+		}
+		if notepad.MaxPoints == 0 {
+			notepad.MaxPoints = maxPoints(notepads[notepad.Name])
+		}
+		configs[i] = notepad
+	}
+	return configs
+}
+
+// thresholds - grade thresholds ordered from the best
+func (gradebook *Gradebook) thresholds() []GradeThreshold {
+	thresholds := gradebook.Grades
+	if len(thresholds) == 0 {
+		thresholds = DefaultGrades
+	}
+
+	sorted := make([]GradeThreshold, len(thresholds))
+	copy(sorted, thresholds)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Min > sorted[j].Min
+	})
+	return sorted
+}
+
+func (gradebook *Gradebook) score(submission Submission, maxPoints float64) NotepadScore {
+	score := NotepadScore{Points: submission.Points, Bonus: submission.Bonus, Counted: true}
+
+	switch gradebook.Bonus {
+	case BonusIgnore:
+		score.Score = submission.Points
+	case BonusCap:
+		score.Score = math.Min(submission.Points+submission.Bonus, maxPoints)
+	default:
+		score.Score = submission.Points + submission.Bonus
+	}
+
+	if maxPoints > 0 {
+		score.Share = score.Score / maxPoints
+	}
+	return score
+}
+
+func (gradebook *Gradebook) applyRule(rule *GradingRule, notepads []GradebookNotepad, grade *StudentGrade) {
+	var matched []string
+	for _, notepad := range notepads {
+		if ruleMatches(rule, notepad.Name) {
+			matched = append(matched, notepad.Name)
+		}
+	}
+
+	switch rule.Type {
+	case RuleBest:
+		sort.SliceStable(matched, func(i, j int) bool {
+			return grade.Scores[matched[i]].Share > grade.Scores[matched[j]].Share
+		})
+		for i, name := range matched {
+			if i >= rule.Count {
+				score := grade.Scores[name]
+				score.Counted = false
+				grade.Scores[name] = score
+			}
+		}
+	case RuleMin:
+		for _, name := range matched {
+			if grade.Scores[name].Share < rule.Share {
+				grade.Failed = append(grade.Failed, fmt.Sprintf("%s < %g%%", name, rule.Share*100))
+			}
+		}
+	}
+}
+
+func ruleMatches(rule *GradingRule, notepad string) bool {
+	if len(rule.Notepads) == 0 {
+		return true
+	}
+	for _, pattern := range rule.Notepads {
+		if ok, _ := path.Match(pattern, notepad); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func gradebookStudent(id uuid.UUID, points, bonus float64) StudentInfo {
+	return StudentInfo{ID: id, Submissions: []Submission{{Index: 0, Points: points, Bonus: bonus, Final: true}}}
+}
+
+func TestGradebook_Grade(t *testing.T) {
+	// GIVEN
+	alice, bob := uuid.New(), uuid.New()
+	gradebook := Gradebook{
+		Notepads: []GradebookNotepad{
+			{Name: "hw01", MaxPoints: 10},
+			{Name: "hw02", MaxPoints: 10},
+			{Name: "hw03", MaxPoints: 10},
+			{Name: "exam", MaxPoints: 20, Weight: 2},
+		},
+		Bonus: BonusCap,
+		Rules: []GradingRule{
+			{Type: RuleBest, Count: 2, Notepads: []string{"hw*"}},
+			{Type: RuleMin, Share: 0.5, Notepads: []string{"exam"}},
+		},
+	}
+	notepads := map[string][]StudentInfo{
+		"hw01": {gradebookStudent(alice, 8, 5), gradebookStudent(bob, 2, 0)},
+		"hw02": {gradebookStudent(alice, 6, 0)},
+		"hw03": {gradebookStudent(alice, 2, 0), gradebookStudent(bob, 10, 0)},
+		"exam": {gradebookStudent(alice, 18, 0), gradebookStudent(bob, 8, 0)},
+	}
+
+	// WHEN
+	grades, err := gradebook.Grade(notepads)
+
+	// THEN
+	if err != nil {
+		t.Fatalf("FAIL: Unable to grade: %v", err)
+	}
+	if len(grades) != 2 || grades[0].StudentID != alice || grades[1].StudentID != bob {
+		t.Fatalf("FAIL: Expected alice and bob ordered by the total, got %+v", grades)
+	}
+
+	// alice: hw01 capped at 10, hw02 6, hw03 dropped, exam 18/20 weighted twice
+	assertFloat(t, "alice hw01", 10, grades[0].Scores["hw01"].Score)
+	if grades[0].Scores["hw03"].Counted {
+		t.Errorf("FAIL: The worst homework should not be counted")
+	}
+	assertFloat(t, "alice total", (1+0.6+2*0.9)/4*100, grades[0].Total)
+	if grades[0].Grade != "B" {
+		t.Errorf("FAIL: Expected grade B, got %s", grades[0].Grade)
+	}
+
+	// bob: hw02 missing, exam below the half
+	if !grades[1].Scores["hw02"].Missing {
+		t.Errorf("FAIL: The bob's hw02 should be missing")
+	}
+	if grades[1].Grade != "F" || len(grades[1].Failed) != 1 {
+		t.Errorf("FAIL: Expected failed grade F, got %+v", grades[1])
+	}
+}
+
+func TestGradebook_DefaultsAndThresholds(t *testing.T) {
+	// GIVEN
+	student := uuid.New()
+	gradebook := Gradebook{
+		Notepads: []GradebookNotepad{{Name: "hw01"}},
+		Bonus:    BonusAdd,
+		Grades:   []GradeThreshold{{Grade: "fail", Min: 0}, {Grade: "pass", Min: 60}},
+	}
+	notepads := map[string][]StudentInfo{
+		"hw01": {gradebookStudent(student, 6, 1), gradebookStudent(uuid.New(), 10, 0)},
+	}
+
+	// WHEN
+	grades, err := gradebook.Grade(notepads)
+
+	// THEN
+	if err != nil {
+		t.Fatalf("FAIL: Unable to grade: %v", err)
+	}
+	// the max points default to the max in the notepad, the bonus is added
+	assertFloat(t, "total", 70, grades[1].Total)
+	if grades[1].StudentID != student || grades[1].Grade != "pass" {
+		t.Errorf("FAIL: Expected the student to pass, got %+v", grades[1])
+	}
+}
+
+func TestGradebook_Validate(t *testing.T) {
+	invalid := []Gradebook{
+		{},
+		{Notepads: []GradebookNotepad{{Name: "hw01"}, {Name: "hw01"}}},
+		{Notepads: []GradebookNotepad{{Name: "hw01"}}, Bonus: "double"},
+		{Notepads: []GradebookNotepad{{Name: "hw01"}}, Rules: []GradingRule{{Type: RuleBest}}},
+		{Notepads: []GradebookNotepad{{Name: "hw01"}}, Rules: []GradingRule{{Type: RuleMin, Share: 50}}},
+		{Notepads: []GradebookNotepad{{Name: "hw01"}}, Rules: []GradingRule{{Type: "worst"}}},
+	}
+
+	for i := range invalid {
+		if err := invalid[i].Validate(); err == nil {
+			t.Errorf("FAIL: Expected the gradebook %+v to be invalid", invalid[i])
+		}
+	}
+}
+
+func TestLoadGradebook(t *testing.T) {
+	// GIVEN
+	dir, err := ioutil.TempDir("", "gradebook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "gradebook.yaml")
+	content := `
+notepads:
+  - name: hw01
+    weight: 1
+    max_points: 10
+  - name: hw02
+bonus: ignore
+rules:
+  - type: best
+    count: 1
+    notepads: ["hw*"]
+grades:
+  - {grade: A, min: 90}
+  - {grade: F, min: 0}
+`
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	gradebook, err := LoadGradebook(file)
+
+	// THEN
+	if err != nil {
+		t.Fatalf("FAIL: Unable to load the gradebook: %v", err)
+	}
+	if len(gradebook.Notepads) != 2 || gradebook.Notepads[0].MaxPoints != 10 || gradebook.Bonus != BonusIgnore {
+		t.Errorf("FAIL: Unexpected gradebook %+v", gradebook)
+	}
+	if len(gradebook.Rules) != 1 || gradebook.Rules[0].Count != 1 || len(gradebook.Grades) != 2 {
+		t.Errorf("FAIL: Unexpected rules or grades %+v", gradebook)
+	}
+}
@@ -0,0 +1,181 @@
+package core
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// IS bulk import file formats
+const (
+	// ISImportText - one "UCO:value" line per student, the format of the IS notepad import from the text file
+	ISImportText = "text"
+	// ISImportCSV - two columns uco,value with the header, readable by the push command too
+	ISImportCSV = "csv"
+)
+
+// Imported values of the student's grade
+const (
+	// ISValueGrade - the grade
+	ISValueGrade = "grade"
+	// ISValueTotal - the total percentage (see StudentGrade.Total) in the IS notepad points notation,
+	// "*85.5" is 85.5 % of the gradebook, not the notepad points
+	ISValueTotal = "total"
+)
+
+// ISGrades - the grades accepted by the IS grade import by default
+var ISGrades = []string{"A", "B", "C", "D", "E", "F", "X"}
+
+var (
+	ucoPattern      = regexp.MustCompile(`^[0-9]+$`)
+	isPointsPattern = regexp.MustCompile(`^\*-?[0-9]+(\.[0-9]+)?$`)
+)
+
+// ISImportEntry - one student's line of the import
+type ISImportEntry struct {
+	Uco       string    `json:"uco"`
+	StudentID uuid.UUID `json:"student_id"`
+	Value     string    `json:"value"`
+	// Problems - why the entry can not be imported, empty when valid
+	Problems []string `json:"problems,omitempty"`
+}
+
+// ISImport - UCO keyed values to be imported to the IS
+type ISImport struct {
+	Value   string          `json:"value"`
+	Entries []ISImportEntry `json:"entries"`
+	// Unmapped - students missing in the students register, they can not be imported
+	Unmapped []uuid.UUID `json:"unmapped,omitempty"`
+}
+
+// NewISImport - maps the students' grades back to their UCOs by the students register
+//
+// The entries are sorted by UCO (numerically).
+func NewISImport(grades []StudentGrade, register *StudentsRegister, value string) (ISImport, error) {
+	if value != ISValueGrade && value != ISValueTotal {
+		return ISImport{}, fmt.Errorf("unknown imported value '%s'", value)
+	}
+
+	ucos := make(map[uuid.UUID]string, len(register.Users))
+	for uco, id := range register.Users {
+		ucos[id] = uco
+	}
+
+	result := ISImport{Value: value}
+	for i := range grades {
+		grade := &grades[i]
+		uco, ok := ucos[grade.StudentID]
+		if !ok {
+			result.Unmapped = append(result.Unmapped, grade.StudentID)
+			continue
+		}
+
+		entry := ISImportEntry{Uco: uco, StudentID: grade.StudentID, Value: grade.Grade}
+		if value == ISValueTotal {
+			entry.Value = "*" + strconv.FormatFloat(math.Round(grade.Total*100)/100, 'f', -1, 64)
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+
+	sort.SliceStable(result.Entries, func(i, j int) bool {
+		return lessUco(result.Entries[i].Uco, result.Entries[j].Uco)
+	})
+	return result, nil
+}
+
+// lessUco - orders the UCOs numerically, the non-numeric ones after them as strings
+func lessUco(a, b string) bool {
+	first, errA := strconv.ParseUint(a, 10, 64)
+	second, errB := strconv.ParseUint(b, 10, 64)
+	switch {
+	case errA == nil && errB == nil:
+		return first < second
+	case errA == nil || errB == nil:
+		return errA == nil
+	default:
+		return a < b
+	}
+}
+
+// Validate - checks the entries' UCOs and values, it returns the number of the invalid entries
+//
+// The grades have to be one of the allowed (case-sensitive, ISGrades when empty), the totals have to be
+// in the IS points notation. When the known UCOs are provided (e.g. the students of the target notepad),
+// the other UCOs are reported as unknown.
+func (isImport *ISImport) Validate(allowed []string, known map[string]bool) int {
+	if len(allowed) == 0 {
+		allowed = ISGrades
+	}
+	grades := make(map[string]bool, len(allowed))
+	for _, grade := range allowed {
+		grades[grade] = true
+	}
+
+	invalid := 0
+	seen := make(map[string]bool)
+	for i := range isImport.Entries {
+		entry := &isImport.Entries[i]
+		entry.Problems = nil
+
+		switch {
+		case !ucoPattern.MatchString(entry.Uco):
+			entry.Problems = append(entry.Problems, "invalid UCO")
+		case known != nil && !known[entry.Uco]:
+			entry.Problems = append(entry.Problems, "unknown UCO")
+		case seen[entry.Uco]:
+			entry.Problems = append(entry.Problems, "duplicate UCO")
+		}
+		seen[entry.Uco] = true
+
+		if isImport.Value == ISValueGrade && !grades[entry.Value] {
+			entry.Problems = append(entry.Problems, fmt.Sprintf("grade '%s' is not allowed", entry.Value))
+		}
+		if isImport.Value == ISValueTotal && !isPointsPattern.MatchString(entry.Value) {
+			entry.Problems = append(entry.Problems, fmt.Sprintf("invalid total '%s'", entry.Value))
+		}
+
+		if len(entry.Problems) > 0 {
+			invalid++
+		}
+	}
+	return invalid
+}
+
+// Write - writes the valid entries in the format (ISImportText or ISImportCSV)
+func (isImport *ISImport) Write(w io.Writer, format string) error {
+	switch format {
+	case ISImportText:
+		for _, entry := range isImport.Entries {
+			if len(entry.Problems) > 0 {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "%s:%s\n", entry.Uco, entry.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ISImportCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write([]string{"uco", isImport.Value}); err != nil {
+			return err
+		}
+		for _, entry := range isImport.Entries {
+			if len(entry.Problems) > 0 {
+				continue
+			}
+			if err := writer.Write([]string{entry.Uco, entry.Value}); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return fmt.Errorf("unknown import format '%s'", format)
+	}
+}
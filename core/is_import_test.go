@@ -0,0 +1,88 @@
+package core
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewISImport(t *testing.T) {
+	// GIVEN
+	alice, bob, unknown := uuid.New(), uuid.New(), uuid.New()
+	register := NewStudentsRegister()
+	register.Register("1234567", alice)
+	register.Register("99999", bob)
+	grades := []StudentGrade{
+		{StudentID: alice, Total: 91.256, Grade: "A"},
+		{StudentID: unknown, Total: 50, Grade: "E"},
+		{StudentID: bob, Total: 40, Grade: "F"},
+	}
+
+	// WHEN
+	byGrade, err := NewISImport(grades, &register, ISValueGrade)
+	if err != nil {
+		t.Fatalf("FAIL: Unable to create the import: %v", err)
+	}
+	byTotal, _ := NewISImport(grades, &register, ISValueTotal)
+
+	// THEN
+	if len(byGrade.Entries) != 2 || byGrade.Entries[0].Uco != "99999" || byGrade.Entries[1].Value != "A" {
+		t.Errorf("FAIL: Unexpected entries sorted numerically by UCO %+v", byGrade.Entries)
+	}
+	if len(byGrade.Unmapped) != 1 || byGrade.Unmapped[0] != unknown {
+		t.Errorf("FAIL: Expected the unknown student to be unmapped, got %v", byGrade.Unmapped)
+	}
+	if byTotal.Entries[1].Value != "*91.26" {
+		t.Errorf("FAIL: Expected the total *91.26, got %s", byTotal.Entries[1].Value)
+	}
+	if _, err := NewISImport(grades, &register, "points"); err == nil {
+		t.Errorf("FAIL: Expected the unknown value to fail")
+	}
+}
+
+func TestISImport_ValidateAndWrite(t *testing.T) {
+	// GIVEN
+	isImport := ISImport{Value: ISValueGrade, Entries: []ISImportEntry{
+		{Uco: "123456", Value: "A"},
+		{Uco: "234567", Value: "pass"},
+		{Uco: "x1", Value: "B"},
+		{Uco: "345678", Value: "C"},
+		{Uco: "999999", Value: "E"},
+	}}
+	known := map[string]bool{"123456": true, "234567": true, "345678": true}
+
+	// WHEN
+	invalid := isImport.Validate(nil, known)
+	var text, table bytes.Buffer
+	textErr := isImport.Write(&text, ISImportText)
+	csvErr := isImport.Write(&table, ISImportCSV)
+
+	// THEN
+	if invalid != 3 {
+		t.Errorf("FAIL: Expected 3 invalid entries, got %d: %+v", invalid, isImport.Entries)
+	}
+	if textErr != nil || text.String() != "123456:A\n345678:C\n" {
+		t.Errorf("FAIL: Unexpected text import %q (%v)", text.String(), textErr)
+	}
+	if csvErr != nil || table.String() != "uco,grade\n123456,A\n345678,C\n" {
+		t.Errorf("FAIL: Unexpected csv import %q (%v)", table.String(), csvErr)
+	}
+}
+
+func TestISImport_ValidateTotal(t *testing.T) {
+	// GIVEN
+	isImport := ISImport{Value: ISValueTotal, Entries: []ISImportEntry{
+		{Uco: "123456", Value: "*10.5"},
+		{Uco: "123456", Value: "*7"},
+		{Uco: "234567", Value: "10"},
+	}}
+
+	// WHEN
+	invalid := isImport.Validate(nil, nil)
+
+	// THEN
+	if invalid != 2 || len(isImport.Entries[0].Problems) != 0 {
+		t.Errorf("FAIL: Expected the duplicate and the invalid total, got %+v", isImport.Entries)
+	}
+}
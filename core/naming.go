@@ -0,0 +1,192 @@
+package core
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+/*
+Result naming scheme
+
+	<name>.<timestamp>.<ext>
+	<name>.<ext>
+
+- name      - notepad shortname, may contain dots, must not contain path separators
+- timestamp - time in the TimestampFormat (2006-01-02T15-04-05)
+- ext       - extension, may be followed by the compression extension (xml.gz)
+
+The timestamp is the last dot-separated segment matching the TimestampFormat,
+so "hw.01.2020-03-01T10-00-00.xml.gz" is the "hw.01" notepad with the "xml.gz" extension.
+The names without the timestamp take only the last segment as the extension
+(two segments for the compressed files).
+*/
+
+// TimestampFormat - format of the result timestamps
+const TimestampFormat = "2006-01-02T15-04-05"
+
+var resultNamePattern = regexp.MustCompile(`^(.+)\.(\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2})\.([^.]+(?:\.[^.]+)*)$`)
+
+// compressionExtensions - extensions kept together with the preceding extension (the ones supported by decompress)
+var compressionExtensions = map[string]bool{
+	"gz":  true,
+	"bz2": true,
+}
+
+// legacyTimestampLayouts - timestamps used by the older versions and scripts, see MigrateResultNames
+var legacyTimestampLayouts = []struct {
+	pattern *regexp.Regexp
+	layout  string
+}{
+	{regexp.MustCompile(`^(.+)\.(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2})\.(.+)$`), "2006-01-02T15:04:05"},
+	{regexp.MustCompile(`^(.+)\.(\d{4}-\d{2}-\d{2}_\d{2}-\d{2}-\d{2})\.(.+)$`), "2006-01-02_15-04-05"},
+	{regexp.MustCompile(`^(.+)\.(\d{4}-\d{2}-\d{2}T\d{2}-\d{2})\.(.+)$`), "2006-01-02T15-04"},
+	{regexp.MustCompile(`^(.+)\.(\d{8}T\d{6})\.(.+)$`), "20060102T150405"},
+	{regexp.MustCompile(`^(.+)\.(\d{8}-\d{6})\.(.+)$`), "20060102-150405"},
+}
+
+// FormatResultName - full name of the result item
+func FormatResultName(name, timestamp, ext string) string {
+	if timestamp == "" {
+		return fmt.Sprintf("%s.%s", name, ext)
+	}
+	return fmt.Sprintf("%s.%s.%s", name, timestamp, ext)
+}
+
+// ParseResultName - parses the full name of the result item (see the naming scheme)
+func ParseResultName(fullName string) (ResultItem, error) {
+	if fullName == "" || strings.ContainsAny(fullName, `/\`) || isTemporaryFile(fullName) {
+		return ResultItem{}, fmt.Errorf("invalid result name '%s'", fullName)
+	}
+
+	if match := resultNamePattern.FindStringSubmatch(fullName); match != nil {
+		if _, err := time.Parse(TimestampFormat, match[2]); err != nil {
+			return ResultItem{}, fmt.Errorf("invalid timestamp in the result name '%s': %v", fullName, err)
+		}
+		return NewResultItem(match[1], match[2], match[3]), nil
+	}
+
+	parts := strings.Split(fullName, ".")
+	if len(parts) < 2 {
+		return ResultItem{}, fmt.Errorf("missing extension in the result name '%s'", fullName)
+	}
+
+	extParts := 1
+	if compressionExtensions[parts[len(parts)-1]] && len(parts) > 2 {
+		extParts = 2
+	}
+
+	name := strings.Join(parts[:len(parts)-extParts], ".")
+	ext := strings.Join(parts[len(parts)-extParts:], ".")
+	if name == "" || strings.Contains("."+ext+".", "..") {
+		return ResultItem{}, fmt.Errorf("invalid result name '%s'", fullName)
+	}
+
+	return NewResultItem(name, "", ext), nil
+}
+
+// BaseExt - the extension without the compression (xml for xml.gz)
+func (item *ResultItem) BaseExt() string {
+	if compression := item.Compression(); compression != "" {
+		return strings.TrimSuffix(item.Ext, "."+compression)
+	}
+	return item.Ext
+}
+
+// Compression - the compression extension (gz for xml.gz), empty for the uncompressed items
+func (item *ResultItem) Compression() string {
+	index := strings.LastIndex(item.Ext, ".")
+	if index < 0 {
+		return ""
+	}
+	if compression := item.Ext[index+1:]; compressionExtensions[compression] {
+		return compression
+	}
+	return ""
+}
+
+// NameMigration - rename of one result file
+type NameMigration struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Error string `json:"error,omitempty"`
+}
+
+// MigrateResultNames - renames the files in the results directory to the naming scheme
+//
+// The files with the legacy timestamps (for example 2020-03-01T10:00:00 or 20200301T100000)
+// are renamed to use the TimestampFormat, the files following the scheme are kept.
+// The unrecognized files and the conflicts are reported with the error and kept.
+func MigrateResultNames(dir string, dryRun bool) ([]NameMigration, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []NameMigration
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || isTemporaryFile(name) {
+			continue
+		}
+
+		if item, err := ParseResultName(name); err == nil && item.TimeStamp != "" {
+			continue
+		}
+
+		target, ok := migrateLegacyName(name)
+		if !ok {
+			if _, err := ParseResultName(name); err != nil {
+				migrations = append(migrations, NameMigration{From: name, Error: "unrecognized result name"})
+			}
+			continue
+		}
+
+		migration := NameMigration{From: name, To: target}
+		entry := log.WithField("from", name).WithField("to", target)
+
+		if _, err := os.Stat(filepath.Join(dir, target)); err == nil {
+			migration.Error = "target already exists"
+		} else if !dryRun {
+			if err := os.Rename(filepath.Join(dir, name), filepath.Join(dir, target)); err != nil {
+				migration.Error = err.Error()
+			}
+		}
+
+		if migration.Error != "" {
+			entry.WithField("error", migration.Error).Warning("Unable to migrate the result name")
+		} else {
+			entry.Info("Migrating the result name")
+		}
+		migrations = append(migrations, migration)
+	}
+
+	return migrations, nil
+}
+
+func migrateLegacyName(fullName string) (string, bool) {
+	for _, legacy := range legacyTimestampLayouts {
+		match := legacy.pattern.FindStringSubmatch(fullName)
+		if match == nil {
+			continue
+		}
+
+		timestamp, err := time.Parse(legacy.layout, match[2])
+		if err != nil {
+			continue
+		}
+
+		target := FormatResultName(match[1], timestamp.Format(TimestampFormat), match[3])
+		if _, err := ParseResultName(target); err != nil {
+			continue
+		}
+		return target, true
+	}
+	return "", false
+}
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestParseResultName(t *testing.T) {
+	cases := []struct {
+		fullName, name, timestamp, ext string
+	}{
+		{"hw01.2020-03-01T10-00-00.xml", "hw01", "2020-03-01T10-00-00", "xml"},
+		{"hw01.2020-03-01T10-00-00.xml.gz", "hw01", "2020-03-01T10-00-00", "xml.gz"},
+		{"hw.01.2020-03-01T10-00-00.json", "hw.01", "2020-03-01T10-00-00", "json"},
+		{"hw01.xml", "hw01", "", "xml"},
+		{"hw.01.xml", "hw.01", "", "xml"},
+		{"hw01.xml.gz", "hw01", "", "xml.gz"},
+	}
+
+	for _, c := range cases {
+		// WHEN
+		item, err := ParseResultName(c.fullName)
+
+		// THEN
+		if err != nil {
+			t.Errorf("FAIL: %s: Found error: %v", c.fullName, err)
+			continue
+		}
+		if item.Name != c.name || item.TimeStamp != c.timestamp || item.Ext != c.ext {
+			t.Errorf("FAIL: %s parsed as %q %q %q", c.fullName, item.Name, item.TimeStamp, item.Ext)
+		}
+		if fullName := item.GetFullName(); fullName != c.fullName {
+			t.Errorf("FAIL: %s formatted back as %s", c.fullName, fullName)
+		}
+	}
+}
+
+func TestParseResultName_Invalid(t *testing.T) {
+	for _, fullName := range []string{"", "hw01", "hw01.", ".xml", "dir/hw01.xml", "hw01.2020-13-01T10-00-00.xml"} {
+		// WHEN
+		_, err := ParseResultName(fullName)
+
+		// THEN
+		if err == nil {
+			t.Errorf("FAIL: %q should not be parsed", fullName)
+		}
+	}
+}
+
+func TestResultItem_Compression(t *testing.T) {
+	// GIVEN
+	item := NewResultItem("hw01", "", "xml.gz")
+
+	// THEN
+	if item.BaseExt() != "xml" || item.Compression() != "gz" {
+		t.Errorf("FAIL: Unexpected base ext %q and compression %q", item.BaseExt(), item.Compression())
+	}
+}
+
+func TestCompressionExtensions_Supported(t *testing.T) {
+	for compression := range compressionExtensions {
+		// WHEN
+		_, err := decompress(compression, []byte{})
+
+		// THEN
+		if err != nil && strings.Contains(err.Error(), "unsupported") {
+			t.Errorf("FAIL: Compression %q is not supported by decompress", compression)
+		}
+	}
+}
+
+func TestMigrateResultNames(t *testing.T) {
+	// GIVEN
+	dir, err := ioutil.TempDir("", "isstat-naming")
+	if err != nil {
+		t.Fatalf("FAIL: Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"hw01.2020-03-01T10:00:00.xml", "hw02.20200302T110000.xml.gz", "hw03.2020-03-03T10-00-00.xml", "notes"} {
+		if err := ioutil.WriteFile(path.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatalf("FAIL: Unable to create file: %v", err)
+		}
+	}
+
+	// WHEN
+	migrations, err := MigrateResultNames(dir, false)
+
+	// THEN
+	if err != nil {
+		t.Fatalf("FAIL: Found error: %v", err)
+	}
+	if len(migrations) != 3 {
+		t.Fatalf("FAIL: Found %d migrations, expected: %d (%v)", len(migrations), 3, migrations)
+	}
+	for _, expected := range []string{"hw01.2020-03-01T10-00-00.xml", "hw02.2020-03-02T11-00-00.xml.gz", "hw03.2020-03-03T10-00-00.xml"} {
+		if _, err := os.Stat(path.Join(dir, expected)); err != nil {
+			t.Errorf("FAIL: Expected file %s: %v", expected, err)
+		}
+	}
+	if migrations[2].From != "notes" || migrations[2].Error == "" {
+		t.Errorf("FAIL: Unrecognized file should be reported: %v", migrations[2])
+	}
+}
@@ -0,0 +1,197 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// HistogramBucket - number of the values in the [From, To) range, the last bucket includes its To
+type HistogramBucket struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Count int     `json:"count"`
+}
+
+// Label - the bucket's lower bound for the buckets of the width 1, the range otherwise
+func (bucket *HistogramBucket) Label() string {
+	if bucket.To-bucket.From == 1 {
+		return fmt.Sprintf("%g", bucket.From)
+	}
+	return fmt.Sprintf("%g-%g", bucket.From, bucket.To)
+}
+
+// TimelinePoint - number of the submissions on the day
+type TimelinePoint struct {
+	Day        time.Time `json:"day"`
+	Count      int       `json:"count"`
+	Cumulative int       `json:"cumulative"`
+}
+
+// NewHistogram - splits the range from zero (or the lower minimum) to the maximum into the equal-width buckets
+//
+// The integer values get the buckets of the width 1 when there are at most twice as many such buckets.
+func NewHistogram(values []float64, bins int) []HistogramBucket {
+	if len(values) == 0 || bins <= 0 {
+		return nil
+	}
+
+	low, high := 0.0, values[0]
+	for _, value := range values {
+		low = math.Min(low, value)
+		high = math.Max(high, value)
+	}
+
+	width := (high - low) / float64(bins)
+	if width == 0 {
+		return []HistogramBucket{{From: low, To: high, Count: len(values)}}
+	}
+	if isIntegral(values) && high-low < float64(2*bins) {
+		width = 1
+		bins = int(high-low) + 1
+	}
+
+	buckets := make([]HistogramBucket, bins)
+	for i := range buckets {
+		buckets[i].From = low + float64(i)*width
+		buckets[i].To = low + float64(i+1)*width
+	}
+
+	for _, value := range values {
+		index := int((value - low) / width)
+		if index >= bins {
+			index = bins - 1
+		}
+		buckets[index].Count++
+	}
+	return buckets
+}
+
+func isIntegral(values []float64) bool {
+	for _, value := range values {
+		if value != math.Trunc(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// FinalPoints - points of the students' final submissions, the students without the submission are skipped
+func FinalPoints(students []StudentInfo) []float64 {
+	var points []float64
+	for i := range students {
+		if submission, ok := students[i].FinalSubmission(); ok {
+			points = append(points, submission.Points)
+		}
+	}
+	return points
+}
+
+// NewSubmissionTimeline - number of the submissions per day (in the location), the days without
+// the submissions between the first and the last one are included
+//
+// The submissions without the time are skipped.
+func NewSubmissionTimeline(students []StudentInfo, location *time.Location) []TimelinePoint {
+	counts := make(map[time.Time]int)
+	for i := range students {
+		for _, submission := range students[i].Submissions {
+			if submission.DateTime.IsZero() {
+				continue
+			}
+			local := submission.DateTime.In(location)
+			counts[time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)]++
+		}
+	}
+
+	if len(counts) == 0 {
+		return nil
+	}
+
+	days := make([]time.Time, 0, len(counts))
+	for day := range counts {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Before(days[j])
+	})
+
+	var timeline []TimelinePoint
+	cumulative := 0
+	for day := days[0]; !day.After(days[len(days)-1]); day = day.AddDate(0, 0, 1) {
+		cumulative += counts[day]
+		timeline = append(timeline, TimelinePoint{Day: day, Count: counts[day], Cumulative: cumulative})
+	}
+	return timeline
+}
+
+// NewSubmissionsPerHour - number of the submissions per hour of the day (in the location)
+//
+// The submissions without the time are skipped.
+func NewSubmissionsPerHour(students []StudentInfo, location *time.Location) [24]int {
+	var hours [24]int
+	for i := range students {
+		for _, submission := range students[i].Submissions {
+			if submission.DateTime.IsZero() {
+				continue
+			}
+			hours[submission.DateTime.In(location).Hour()]++
+		}
+	}
+	return hours
+}
+
+// Lateness - submissions made after the deadline
+type Lateness struct {
+	Deadline    time.Time `json:"deadline"`
+	Submissions int       `json:"submissions"`
+	Late        int       `json:"late"`
+	// LateStudents - students with at least one late submission
+	LateStudents int `json:"late_students"`
+	// FinalLate - students whose final submission is late
+	FinalLate int `json:"final_late"`
+}
+
+// LateShare - share of the late submissions
+func (lateness *Lateness) LateShare() float64 {
+	if lateness.Submissions == 0 {
+		return 0
+	}
+	return float64(lateness.Late) / float64(lateness.Submissions)
+}
+
+// Attempts - number of the submissions of each student
+func Attempts(students []StudentInfo) []float64 {
+	attempts := make([]float64, len(students))
+	for i := range students {
+		attempts[i] = float64(len(students[i].Submissions))
+	}
+	return attempts
+}
+
+// NewLateness - counts the submissions after the deadline, the submissions without the time are not counted
+func NewLateness(students []StudentInfo, deadline time.Time) Lateness {
+	lateness := Lateness{Deadline: deadline}
+	for i := range students {
+		student := &students[i]
+		late := false
+		for _, submission := range student.Submissions {
+			if submission.DateTime.IsZero() {
+				continue
+			}
+			lateness.Submissions++
+			if submission.DateTime.After(deadline) {
+				lateness.Late++
+				late = true
+			}
+		}
+
+		if late {
+			lateness.LateStudents++
+		}
+		if final, ok := student.FinalSubmission(); ok && final.DateTime.After(deadline) {
+			lateness.FinalLate++
+		}
+	}
+	return lateness
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewHistogram_IntegerPoints(t *testing.T) {
+	// WHEN
+	buckets := NewHistogram([]float64{0, 1, 1, 3, 3, 3}, 10)
+
+	// THEN
+	expected := []int{1, 2, 0, 3}
+	if len(buckets) != len(expected) {
+		t.Fatalf("FAIL: Expected %d buckets, got %+v", len(expected), buckets)
+	}
+	for i, count := range expected {
+		if buckets[i].Count != count || buckets[i].From != float64(i) {
+			t.Errorf("FAIL: Bucket %d is %+v, expected from %d with count %d", i, buckets[i], i, count)
+		}
+	}
+}
+
+func TestNewHistogram_IntegerPointsUpToTwiceTheBins(t *testing.T) {
+	// GIVEN
+	values := []float64{0, 15}
+
+	// WHEN
+	unit := NewHistogram(values, 10)
+	equal := NewHistogram(append(values, 25), 10)
+
+	// THEN
+	if len(unit) != 16 || unit[15].From != 15 || unit[15].To != 16 {
+		t.Errorf("FAIL: Expected 16 buckets of the width 1, got %+v", unit)
+	}
+	if len(equal) != 10 || equal[9].To != 25 {
+		t.Errorf("FAIL: Expected 10 equal-width buckets, got %+v", equal)
+	}
+}
+
+func TestNewHistogram_EqualWidth(t *testing.T) {
+	// WHEN
+	buckets := NewHistogram([]float64{0.5, 2.5, 9.5, 10}, 5)
+
+	// THEN
+	if len(buckets) != 5 || buckets[0].Count != 1 || buckets[1].Count != 1 || buckets[4].Count != 2 {
+		t.Errorf("FAIL: Unexpected buckets %+v", buckets)
+	}
+	if buckets[4].To != 10 {
+		t.Errorf("FAIL: Last bucket should end at the maximum, got %v", buckets[4].To)
+	}
+}
+
+func TestNewHistogram_Empty(t *testing.T) {
+	if buckets := NewHistogram(nil, 10); buckets != nil {
+		t.Errorf("FAIL: No values should have no buckets, got %+v", buckets)
+	}
+}
+
+func TestNewSubmissionTimeline(t *testing.T) {
+	// GIVEN
+	day := time.Date(2020, 3, 1, 10, 0, 0, 0, time.UTC)
+	students := []StudentInfo{
+		{Submissions: []Submission{{DateTime: day}, {DateTime: day.Add(2 * time.Hour)}, {}}},
+		{Submissions: []Submission{{DateTime: day.AddDate(0, 0, 2)}}},
+	}
+
+	// WHEN
+	timeline := NewSubmissionTimeline(students, time.UTC)
+
+	// THEN
+	if len(timeline) != 3 {
+		t.Fatalf("FAIL: Expected 3 days including the empty one, got %+v", timeline)
+	}
+	if timeline[0].Count != 2 || timeline[1].Count != 0 || timeline[2].Count != 1 || timeline[2].Cumulative != 3 {
+		t.Errorf("FAIL: Unexpected timeline %+v", timeline)
+	}
+}
+
+func TestNewLateness(t *testing.T) {
+	// GIVEN
+	deadline := time.Date(2020, 3, 8, 23, 59, 0, 0, time.UTC)
+	students := []StudentInfo{
+		{Submissions: []Submission{{Index: 0, DateTime: deadline.Add(-time.Hour)}, {Index: 1, DateTime: deadline.Add(time.Hour), Final: true}}},
+		{Submissions: []Submission{{Index: 0, DateTime: deadline.Add(time.Minute)}, {Index: 1, DateTime: deadline.Add(-time.Minute), Final: true}}},
+		{Submissions: []Submission{{Index: 0, DateTime: deadline.Add(-time.Hour), Final: true}, {Index: 1}}},
+	}
+
+	// WHEN
+	lateness := NewLateness(students, deadline)
+
+	// THEN
+	if lateness.Submissions != 5 || lateness.Late != 2 || lateness.LateStudents != 2 || lateness.FinalLate != 1 {
+		t.Errorf("FAIL: Unexpected lateness %+v", lateness)
+	}
+	assertFloat(t, "late share", 0.4, lateness.LateShare())
+}
+
+func TestNewSubmissionsPerHour(t *testing.T) {
+	// GIVEN
+	day := time.Date(2020, 3, 1, 23, 30, 0, 0, time.UTC)
+	students := []StudentInfo{
+		{Submissions: []Submission{{DateTime: day}, {DateTime: day.AddDate(0, 0, 1)}, {}}},
+		{Submissions: []Submission{{DateTime: day.Add(time.Hour)}}},
+	}
+
+	// WHEN
+	hours := NewSubmissionsPerHour(students, time.UTC)
+
+	// THEN
+	if hours[23] != 2 || hours[0] != 1 {
+		t.Errorf("FAIL: Unexpected hours %v", hours)
+	}
+}
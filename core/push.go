@@ -0,0 +1,161 @@
+package core
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Push actions
+const (
+	PushCreate    = "create"
+	PushUpdate    = "update"
+	PushUnchanged = "unchanged"
+)
+
+// PushChange - planned change of one student's notepad content
+type PushChange struct {
+	Uco     string `json:"uco"`
+	Action  string `json:"action"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+	Written bool   `json:"written"`
+	Error   string `json:"error,omitempty"`
+}
+
+// PlanPush - compares the current notepad content with the new contents
+//
+// The changes are sorted by UCO, students not present in the new contents are not touched.
+func PlanPush(current *NotepadContent, contents []StudentContent) []PushChange {
+	existing := make(map[string]string)
+	for _, student := range current.StudentsContent {
+		existing[student.Uco] = student.Content
+	}
+
+	changes := make([]PushChange, 0, len(contents))
+	for _, student := range contents {
+		change := PushChange{Uco: student.Uco, New: student.Content}
+		old, ok := existing[student.Uco]
+		switch {
+		case !ok || old == "":
+			change.Action = PushCreate
+		case normalizeContent(old) == normalizeContent(student.Content):
+			change.Action = PushUnchanged
+		default:
+			change.Action = PushUpdate
+		}
+		change.Old = old
+		changes = append(changes, change)
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Uco < changes[j].Uco
+	})
+
+	return changes
+}
+
+// Changed - whether the change has to be written to the IS
+func (change *PushChange) Changed() bool {
+	return change.Action != PushUnchanged
+}
+
+// Diff - line based description of the change ("-" removed line, "+" added line)
+func (change *PushChange) Diff() string {
+	var builder strings.Builder
+	if change.Old != "" {
+		for _, line := range strings.Split(normalizeContent(change.Old), "\n") {
+			builder.WriteString("- " + line + "\n")
+		}
+	}
+	for _, line := range strings.Split(normalizeContent(change.New), "\n") {
+		builder.WriteString("+ " + line + "\n")
+	}
+	return builder.String()
+}
+
+func normalizeContent(content string) string {
+	return strings.TrimRight(strings.ReplaceAll(content, "\r\n", "\n"), "\n ")
+}
+
+// LoadStudentContents - loads the UCO keyed contents to be pushed
+//
+// Supported formats (by the file extension):
+// - json - object {"uco": "content"} or list [{"uco": "...", "content": "..."}]
+// - csv  - two columns: uco,content (header row is optional)
+//
+// The duplicate UCOs are rejected, so no content silently replaces another one of the same student.
+func LoadStudentContents(file string) ([]StudentContent, error) {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		return loadStudentContentsJSON(file)
+	case ".csv":
+		return loadStudentContentsCSV(file)
+	default:
+		return nil, fmt.Errorf("unsupported contents file format: %s", file)
+	}
+}
+
+func loadStudentContentsJSON(file string) ([]StudentContent, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var contents []StudentContent
+	if err := json.Unmarshal(data, &contents); err == nil {
+		seen := make(map[string]int)
+		for i, content := range contents {
+			if first, ok := seen[content.Uco]; ok {
+				return nil, fmt.Errorf("%s: duplicate UCO %s in the entry %d, already in the entry %d", file, content.Uco, i+1, first)
+			}
+			seen[content.Uco] = i + 1
+		}
+		return contents, nil
+	}
+
+	var mapping map[string]string
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %v", file, err)
+	}
+
+	for uco, content := range mapping {
+		contents = append(contents, StudentContent{Uco: uco, Content: content})
+	}
+	return contents, nil
+}
+
+func loadStudentContentsCSV(file string) ([]StudentContent, error) {
+	csvFile, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer csvFile.Close()
+
+	reader := csv.NewReader(csvFile)
+	reader.FieldsPerRecord = 2
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %v", file, err)
+	}
+
+	var contents []StudentContent
+	seen := make(map[string]int)
+	for i, record := range records {
+		uco := strings.TrimSpace(record[0])
+		if i == 0 && strings.EqualFold(uco, "uco") {
+			continue
+		}
+		if first, ok := seen[uco]; ok {
+			return nil, fmt.Errorf("%s: duplicate UCO %s on the line %d, already on the line %d", file, uco, i+1, first)
+		}
+		seen[uco] = i + 1
+		contents = append(contents, StudentContent{Uco: uco, Content: record[1]})
+	}
+	return contents, nil
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPlanPush(t *testing.T) {
+	// GIVEN
+	current := NotepadContent{StudentsContent: []StudentContent{
+		{Uco: "3", Content: "same\n"},
+		{Uco: "2", Content: "old"},
+		{Uco: "4", Content: ""},
+	}}
+	contents := []StudentContent{
+		{Uco: "4", Content: "filled"},
+		{Uco: "3", Content: "same"},
+		{Uco: "2", Content: "new"},
+		{Uco: "1", Content: "created"},
+	}
+
+	// WHEN
+	changes := PlanPush(&current, contents)
+
+	// THEN
+	expected := []struct{ uco, action string }{
+		{"1", PushCreate},
+		{"2", PushUpdate},
+		{"3", PushUnchanged},
+		{"4", PushCreate},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("FAIL: Found %d changes, expected: %d", len(changes), len(expected))
+	}
+	for i, exp := range expected {
+		if changes[i].Uco != exp.uco || changes[i].Action != exp.action {
+			t.Errorf("FAIL: Change %d is %s/%s, expected: %s/%s", i, changes[i].Uco, changes[i].Action, exp.uco, exp.action)
+		}
+	}
+	if diff := changes[1].Diff(); diff != "- old\n+ new\n" {
+		t.Errorf("FAIL: Unexpected diff: %q", diff)
+	}
+}
+
+func TestLoadStudentContents_DuplicateUco(t *testing.T) {
+	// GIVEN
+	dir, err := ioutil.TempDir("", "isstat-push")
+	if err != nil {
+		t.Fatalf("FAIL: Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"contents.csv":  "uco,content\n1,first\n2,other\n1,second\n",
+		"contents.json": `[{"uco":"1","content":"first"},{"uco":"1","content":"second"}]`,
+	}
+	expected := map[string]string{
+		"contents.csv":  "duplicate UCO 1 on the line 4, already on the line 2",
+		"contents.json": "duplicate UCO 1 in the entry 2, already in the entry 1",
+	}
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(file, []byte(content), 0600); err != nil {
+			t.Fatalf("FAIL: Unable to write the contents: %v", err)
+		}
+
+		// WHEN
+		contents, err := LoadStudentContents(file)
+
+		// THEN
+		if err == nil || !strings.Contains(err.Error(), expected[name]) {
+			t.Errorf("FAIL: Expected the duplicate UCO error for %s, got %v and %v", name, contents, err)
+		}
+	}
+}
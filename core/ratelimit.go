@@ -0,0 +1,75 @@
+package core
+
+import (
+	"net/url"
+	"sync"
+	"time"
+)
+
+// hostRateLimiters - rate limiters shared by all the clients in the process, keyed by the host
+var hostRateLimiters = struct {
+	sync.Mutex
+	limiters map[string]*RateLimiter
+}{limiters: make(map[string]*RateLimiter)}
+
+// RateLimiter - spaces the requests so at most Rate requests per second are made
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewRateLimiter - creates a new limiter allowing rate requests per second (<= 0 means unlimited)
+func NewRateLimiter(rate float64) *RateLimiter {
+	limiter := &RateLimiter{}
+	if rate > 0 {
+		limiter.interval = time.Duration(float64(time.Second) / rate)
+	}
+	return limiter
+}
+
+// Reserve - reserves the next slot and returns how long the caller has to wait for it
+func (limiter *RateLimiter) Reserve() time.Duration {
+	if limiter.interval == 0 {
+		return 0
+	}
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	now := time.Now()
+	if limiter.next.Before(now) {
+		limiter.next = now
+	}
+
+	wait := limiter.next.Sub(now)
+	limiter.next = limiter.next.Add(limiter.interval)
+	return wait
+}
+
+// Wait - blocks until the next request is allowed
+func (limiter *RateLimiter) Wait() {
+	if wait := limiter.Reserve(); wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// getHostRateLimiter - gets the shared limiter for the url's host
+//
+// The limiter is created on the first use, the rate of the first caller is used for the host.
+func getHostRateLimiter(rawURL string, rate float64) *RateLimiter {
+	host := rawURL
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+
+	hostRateLimiters.Lock()
+	defer hostRateLimiters.Unlock()
+
+	limiter, ok := hostRateLimiters.limiters[host]
+	if !ok {
+		limiter = NewRateLimiter(rate)
+		hostRateLimiters.limiters[host] = limiter
+	}
+	return limiter
+}
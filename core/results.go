@@ -1,10 +1,13 @@
 package core
 
 import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
-	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -14,7 +17,7 @@ import (
 
 // Results - structure to hold the result
 type Results struct {
-	ResultsDir       string
+	Backend          Store
 	WithoutTimestamp bool
 }
 
@@ -31,21 +34,16 @@ func NewResultItem(name, timestamp, ext string) ResultItem {
 	return ResultItem{Name: name, TimeStamp: timestamp, Ext: ext}
 }
 
+// NewResultItemFromFullName - Creates a result item from its full name (see ParseResultName)
+//
+// Empty item is returned when the name does not follow the naming scheme.
 func NewResultItemFromFullName(fullName string) ResultItem {
-	item := ResultItem{}
-
 	log.WithField("fullName", fullName).Debug("Parsing the full name")
-	parts := strings.Split(fullName, ".")
-	if len(parts) == 3 {
-		item.Name = parts[0]
-		item.TimeStamp = parts[1]
-		item.Ext = parts[2]
-	} else if len(parts) == 2 {
-		item.Name = parts[0]
-		item.Ext = parts[1]
-	} else {
-		log.WithField("partsLen", len(parts)).WithField("parts", parts).Error("Unable to split - parts != 3")
-		return item
+
+	item, err := ParseResultName(fullName)
+	if err != nil {
+		log.WithField("fullName", fullName).WithError(err).Debug("Unable to parse the full name")
+		return ResultItem{}
 	}
 
 	log.WithField("item", item).Debug("new Result Item")
@@ -55,36 +53,30 @@ func NewResultItemFromFullName(fullName string) ResultItem {
 
 // GetFullName for the item
 func (item *ResultItem) GetFullName() string {
-	if item.TimeStamp == "" {
-		return item.GetFullNameWithoutTimestamp()
-	}
-	return fmt.Sprintf("%s.%s.%s", item.Name, item.TimeStamp, item.Ext)
+	return FormatResultName(item.Name, item.TimeStamp, item.Ext)
 }
 
+// GetFullNameWithoutTimestamp for the item
 func (item *ResultItem) GetFullNameWithoutTimestamp() string {
-	return fmt.Sprintf("%s.%s", item.Name, item.Ext)
-
+	return FormatResultName(item.Name, "", item.Ext)
 }
 
 func (item *ResultItem) getLogEntry() *log.Entry {
 	return log.WithField("name", item.Name).
 		WithField("timestamp", item.TimeStamp).
 		WithField("ext", item.Ext).
-		WithField("fullname", item.GetFullName)
+		WithField("fullname", item.GetFullName())
 }
 
-// NewResults - Creates a new result holder
+// NewResults - Creates a new result holder stored in the directory
 func NewResults(resultsDir string, withoutTimestamp bool) Results {
-	var err error
-	if resultsDir == "" {
-		resultsDir, err = os.Getwd()
-		if err != nil {
-			log.WithError(err).Warning("Unable to get current working directory")
-		}
-	}
+	return NewResultsWithStore(NewFilesystemStore(resultsDir), withoutTimestamp)
+}
 
-	log.WithField("dir", resultsDir).Info("Results dir location")
-	return Results{ResultsDir: resultsDir, WithoutTimestamp: withoutTimestamp}
+// NewResultsWithStore - Creates a new result holder using the storage backend
+func NewResultsWithStore(store Store, withoutTimestamp bool) Results {
+	log.WithField("location", store.Location()).Info("Results location")
+	return Results{Backend: store, WithoutTimestamp: withoutTimestamp}
 }
 
 // Store - store the content to the file
@@ -93,26 +85,22 @@ func (results *Results) Store(item *ResultItem) error {
 		item.TimeStamp = GetCurrentTimestamp()
 	}
 
-	fullPath := results.GetPath(item)
-	item.getLogEntry().WithField("path", fullPath).Info("Storing result")
-
-	if item.TimeStamp == "" {
-		// DO NOT STORE
-		return nil
-	}
+	item.getLogEntry().WithField("location", results.Location()).Info("Storing result")
 
 	if results.WithoutTimestamp {
 		_ = results.StoreWithoutTimestamp(item)
 	}
 
-	return ioutil.WriteFile(fullPath, item.Data, 0644)
+	return results.Backend.Put(item)
 }
 
+// StoreWithoutTimestamp - stores the copy of the item without the timestamp (name.ext)
 func (results *Results) StoreWithoutTimestamp(item *ResultItem) error {
-	pth := results.GetPathWithoutTimestamp(item)
-	item.getLogEntry().WithField("path", pth).Info("Storing result without timestamp")
+	withoutTimestamp := *item
+	withoutTimestamp.TimeStamp = ""
+	item.getLogEntry().WithField("fullname", withoutTimestamp.GetFullName()).Info("Storing result without timestamp")
 
-	if err := ioutil.WriteFile(pth, item.Data, 0644); err != nil {
+	if err := results.Backend.Put(&withoutTimestamp); err != nil {
 		item.getLogEntry().WithError(err).Error("Unable to store without timestamp")
 		return err
 	}
@@ -121,76 +109,49 @@ func (results *Results) StoreWithoutTimestamp(item *ResultItem) error {
 
 // Get - get item's content
 func (results *Results) Get(item *ResultItem) (*ResultItem, error) {
-	fullPath := results.GetPath(item)
-
-	entry := log.WithField("fullPath", fullPath)
-
-	data, err := ioutil.ReadFile(fullPath)
+	return results.Backend.Get(item)
+}
 
+// GetContent - Gets content as bytes, the compressed items are decompressed
+func (results *Results) GetContent(item *ResultItem) ([]byte, error) {
+	loaded, err := results.Backend.Get(item)
 	if err != nil {
-		entry.WithError(err).Error("Unable to read a file")
 		return nil, err
 	}
-
-	item.Data = data
-
-	return item, nil
+	return decompress(loaded.Compression(), loaded.Data)
 }
 
-// List all Result entries
-func (results *Results) List() (items []ResultItem, err error) {
-	files, err := results.ListPaths()
-
-	if err != nil {
-		return nil, err
-	}
-	for _, fpath := range files {
-		fname := path.Base(fpath)
-		log.WithField("path", fpath).Debug("Processing path")
-		var name, datetime, ext string
-		if n, err := fmt.Sscanf(fname, "%s.%s.%s", name, datetime, ext); err != nil {
-			log.WithError(err).
-				WithField("n", n).
-				WithField("filename", fname).
-				Error("Unable to parse file name")
+func decompress(compression string, data []byte) ([]byte, error) {
+	var reader io.Reader
+	switch compression {
+	case "":
+		return data, nil
+	case "gz":
+		gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
 			return nil, err
 		}
-		items = append(items, NewResultItem(name, datetime, ext))
+		defer gzipReader.Close()
+		reader = gzipReader
+	case "bz2":
+		reader = bzip2.NewReader(bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("unsupported compression '%s'", compression)
 	}
-	return items, nil
+	return ioutil.ReadAll(reader)
 }
 
-// ListPaths all path in the results dir
-func (results *Results) ListPaths() (paths []string, err error) {
-	files, err := ioutil.ReadDir(results.ResultsDir)
-
-	if err != nil {
-		return paths, err
-	}
-
-	for _, f := range files {
-
-		fp := path.Join(results.ResultsDir, f.Name())
-		paths = append(paths, fp)
-	}
-	return paths, nil
-}
-
-// GetPath - gets a full result path
-func (results *Results) GetPath(item *ResultItem) string {
-	return path.Join(results.ResultsDir, item.GetFullName())
-}
-
-func (results *Results) GetPathWithoutTimestamp(item *ResultItem) string {
-	return path.Join(results.ResultsDir, item.GetFullNameWithoutTimestamp())
+// Delete - removes the item from the results
+func (results *Results) Delete(item *ResultItem) error {
+	return results.Backend.Delete(item)
 }
 
-// Gets content as bytes
-func (results *Results) GetContent(item *ResultItem) ([]byte, error) {
-	fp := results.GetPath(item)
-	return ioutil.ReadFile(fp)
+// List all Result entries
+func (results *Results) List() ([]ResultItem, error) {
+	return results.Backend.List()
 }
 
+// GlobAll - full names of the items matching any of the patterns
 func (results *Results) GlobAll(notepads []string) []string {
 	var items []string
 
@@ -202,28 +163,67 @@ func (results *Results) GlobAll(notepads []string) []string {
 	return items
 }
 
+// Glob - full names of the items matching the pattern
 func (results *Results) Glob(pattern string) []string {
-	var filenames []string
+	return results.Backend.Glob(pattern)
+}
 
-	fpath := path.Join(results.ResultsDir, pattern)
-	log.WithField("pattern", pattern).WithField("patternPath", fpath).Debug("Globing pattern")
-	files, err := filepath.Glob(fpath)
+// Location - where the results are stored
+func (results *Results) Location() string {
+	return results.Backend.Location()
+}
 
+// Close - releases the storage backend
+func (results *Results) Close() error {
+	return results.Backend.Close()
+}
+
+// Temporary files of the WriteFileAtomic (.<name>.<random>.tmp), they are never listed as the results
+const (
+	tmpFilePrefix = "."
+	tmpFileSuffix = ".tmp"
+)
+
+// isTemporaryFile - whether the file is the temporary file of the WriteFileAtomic
+func isTemporaryFile(name string) bool {
+	return strings.HasPrefix(name, tmpFilePrefix) && strings.HasSuffix(name, tmpFileSuffix)
+}
+
+// WriteFileAtomic - writes the data to a temporary file in the same directory and renames it over the file
+//
+// Concurrent writers (for example parallel fetch workers) never leave a partially written result,
+// the last rename wins when several writers store the same result.
+func WriteFileAtomic(file string, data []byte, perm os.FileMode) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(file), tmpFilePrefix+filepath.Base(file)+".*"+tmpFileSuffix)
 	if err != nil {
-		log.WithError(err).WithField("pattern", pattern).Warning("Glob error occurred")
-		return filenames
+		return err
 	}
 
-	log.WithField("files", files).Debug("Glob found files")
+	tmpName := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+		return err
+	}
 
-	for _, file := range files {
-		filenames = append(filenames, filepath.Base(file))
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpName)
+		return err
 	}
 
-	return filenames
+	if err := os.Chmod(tmpName, perm); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+
+	if err := os.Rename(tmpName, file); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	return nil
 }
 
 // GetCurrentTimestamp - Gets a current timestamp
 func GetCurrentTimestamp() string {
-	return time.Now().Format("2006-01-02T15-04-05")
+	return time.Now().Format(TimestampFormat)
 }
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestResultsStore_Concurrent(t *testing.T) {
+	// GIVEN
+	dir, err := ioutil.TempDir("", "isstat-results")
+	if err != nil {
+		t.Fatalf("FAIL: Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	results := NewResults(dir, false)
+	const count = 20
+
+	// WHEN
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			item := NewResultItem(fmt.Sprintf("hw%02d", i), "2020-03-01T10-00-00", "xml")
+			item.Data = []byte(fmt.Sprintf("<content>%d</content>", i))
+			if err := results.Store(&item); err != nil {
+				t.Errorf("FAIL: Unable to store item: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// THEN
+	items, err := results.List()
+	if err != nil {
+		t.Fatalf("FAIL: Unable to list items: %v", err)
+	}
+	if len(items) != count {
+		t.Errorf("FAIL: Found %d items, expected: %d", len(items), count)
+	}
+
+	item := NewResultItem("hw07", "2020-03-01T10-00-00", "xml")
+	data, err := results.GetContent(&item)
+	if err != nil {
+		t.Fatalf("FAIL: Unable to read item: %v", err)
+	}
+	if string(data) != "<content>7</content>" {
+		t.Errorf("FAIL: Content is %q", string(data))
+	}
+}
+
+func TestResultsStore_IgnoresTemporaryFiles(t *testing.T) {
+	// GIVEN
+	dir, err := ioutil.TempDir("", "isstat-results")
+	if err != nil {
+		t.Fatalf("FAIL: Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	results := NewResults(dir, false)
+	item := NewResultItem("hw01", "2020-03-01T10-00-00", "xml")
+	item.Data = []byte("<content/>")
+	if err := results.Store(&item); err != nil {
+		t.Fatalf("FAIL: Unable to store item: %v", err)
+	}
+	// the write in progress of another process
+	tmp := filepath.Join(dir, ".hw01.2020-03-02T10-00-00.xml.123456.tmp")
+	if err := ioutil.WriteFile(tmp, []byte("<partial"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// WHEN
+	items, listErr := results.List()
+	globbed := results.Glob("*")
+
+	// THEN
+	if listErr != nil || len(items) != 1 || items[0].GetFullName() != item.GetFullName() {
+		t.Errorf("FAIL: Expected only the stored item to be listed, got %+v (%v)", items, listErr)
+	}
+	if len(globbed) != 1 || globbed[0] != item.GetFullName() {
+		t.Errorf("FAIL: Expected only the stored item to be globbed, got %v", globbed)
+	}
+	if _, err := ParseResultName(filepath.Base(tmp)); err == nil {
+		t.Errorf("FAIL: Expected the temporary file name to be invalid")
+	}
+}
@@ -11,6 +11,9 @@ import "time"
 type StudentInfo struct {
 	ID          uuid.UUID    `json:"uid"`
 	Submissions []Submission `json:"submissions"`
+	// EditedByOther - the notepad entry was last edited by an account that is not the Kontr (see IsEditedByOther),
+	// the editor's account (UCO) itself is not kept in the pseudonymised data
+	EditedByOther bool `json:"edited_by_other,omitempty"`
 }
 
 // Submission - representation of the one student submission
@@ -36,3 +39,19 @@ func UnmarshalStudentInfo(content []byte) ([]StudentInfo, error) {
 
 	return studentInfo, nil
 }
+
+// RemapStudentIDs - replaces the students' IDs using the mapping
+//
+// It returns the number of students whose ID was not found in the mapping (those are kept).
+func RemapStudentIDs(students []StudentInfo, mapping map[uuid.UUID]uuid.UUID) int {
+	missing := 0
+	for i := range students {
+		newID, ok := mapping[students[i].ID]
+		if !ok {
+			missing++
+			continue
+		}
+		students[i].ID = newID
+	}
+	return missing
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Store kinds
+const (
+	// FilesystemStoreKind - one file per result item in the results directory
+	FilesystemStoreKind = "filesystem"
+	// BoltStoreKind - all the result items in one embedded database file
+	BoltStoreKind = "bolt"
+)
+
+// Store - storage backend of the result items
+type Store interface {
+	// Put - stores the item's data under its full name, the existing item is overwritten
+	Put(item *ResultItem) error
+	// Get - loads the item's data
+	Get(item *ResultItem) (*ResultItem, error)
+	// List - lists all the stored items (without the data)
+	List() ([]ResultItem, error)
+	// Delete - removes the item
+	Delete(item *ResultItem) error
+	// Glob - full names of the stored items matching the shell pattern
+	Glob(pattern string) []string
+	// Location - human readable location of the store
+	Location() string
+	// Close - releases the resources held by the store
+	Close() error
+}
+
+// OpenStore - opens the store of the provided kind at the location
+func OpenStore(kind, location string) (Store, error) {
+	switch strings.ToLower(kind) {
+	case "", FilesystemStoreKind:
+		return NewFilesystemStore(location), nil
+	case BoltStoreKind:
+		return OpenBoltStore(location)
+	default:
+		return nil, fmt.Errorf("unknown store kind '%s'", kind)
+	}
+}
+
+// globNames - names matching the shell pattern, invalid pattern matches nothing
+func globNames(pattern string, names []string) ([]string, error) {
+	var matched []string
+	for _, name := range names {
+		ok, err := path.Match(pattern, name)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, name)
+		}
+	}
+	return matched, nil
+}
+
+// listItems - converts the full names to the result items, the names that are not items are skipped
+func listItems(names []string) []ResultItem {
+	var items []ResultItem
+	for _, name := range names {
+		item := NewResultItemFromFullName(name)
+		if item.Name == "" {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestFilesystemStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "isstat-store")
+	if err != nil {
+		t.Fatalf("FAIL: Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	testStore(t, NewFilesystemStore(dir))
+}
+
+func TestBoltStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "isstat-store")
+	if err != nil {
+		t.Fatalf("FAIL: Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := OpenBoltStore(path.Join(dir, "results.db"))
+	if err != nil {
+		t.Fatalf("FAIL: Unable to open the store: %v", err)
+	}
+	defer store.Close()
+
+	testStore(t, store)
+}
+
+func TestBoltStore_NotLockedBetweenOperations(t *testing.T) {
+	// GIVEN
+	dir, err := ioutil.TempDir("", "isstat-store")
+	if err != nil {
+		t.Fatalf("FAIL: Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := path.Join(dir, "results.db")
+	store, err := OpenBoltStore(file)
+	if err != nil {
+		t.Fatalf("FAIL: Unable to open the store: %v", err)
+	}
+	defer store.Close()
+	item := NewResultItem("hw01", "2020-03-01T10-00-00", "xml")
+	if err := store.Put(&item); err != nil {
+		t.Fatalf("FAIL: Unable to put the item: %v", err)
+	}
+
+	// WHEN
+	db, err := bolt.Open(file, 0644, &bolt.Options{Timeout: time.Second})
+
+	// THEN
+	if err != nil {
+		t.Fatalf("FAIL: The database is still locked by the open store: %v", err)
+	}
+	_ = db.Close()
+	if names := store.Glob("hw01.*"); len(names) != 1 {
+		t.Errorf("FAIL: Unexpected items: %v", names)
+	}
+}
+
+func testStore(t *testing.T, store Store) {
+	// GIVEN
+	for _, name := range []string{"hw01.2020-03-01T10-00-00.xml", "hw01.2020-03-01T10-00-00.json", "hw02.2020-03-02T10-00-00.xml"} {
+		item := NewResultItemFromFullName(name)
+		item.Data = []byte(name)
+		if err := store.Put(&item); err != nil {
+			t.Fatalf("FAIL: Unable to put %s: %v", name, err)
+		}
+	}
+
+	// WHEN
+	items, err := store.List()
+	globbed := store.Glob("*.xml")
+	loaded, getErr := store.Get(&ResultItem{Name: "hw02", TimeStamp: "2020-03-02T10-00-00", Ext: "xml"})
+
+	// THEN
+	if err != nil || len(items) != 3 {
+		t.Errorf("FAIL: Listed %d items (%v), expected: %d", len(items), err, 3)
+	}
+
+	sort.Strings(globbed)
+	if len(globbed) != 2 || globbed[0] != "hw01.2020-03-01T10-00-00.xml" {
+		t.Errorf("FAIL: Unexpected glob result: %v", globbed)
+	}
+
+	if getErr != nil || string(loaded.Data) != "hw02.2020-03-02T10-00-00.xml" {
+		t.Errorf("FAIL: Unexpected item: %v (%v)", loaded, getErr)
+	}
+
+	// WHEN
+	err = store.Delete(&ResultItem{Name: "hw01", TimeStamp: "2020-03-01T10-00-00", Ext: "json"})
+
+	// THEN
+	if err != nil {
+		t.Errorf("FAIL: Unable to delete: %v", err)
+	}
+	if remaining := store.Glob("hw01.*"); len(remaining) != 1 {
+		t.Errorf("FAIL: Unexpected items after delete: %v", remaining)
+	}
+	if _, err := store.Get(&ResultItem{Name: "hw01", TimeStamp: "2020-03-01T10-00-00", Ext: "json"}); err == nil {
+		t.Error("FAIL: Deleted item should not be found")
+	}
+}
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -9,9 +11,17 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// pseudonymNamespace - namespace of the keyed pseudonyms
+var pseudonymNamespace = uuid.MustParse("6f1c8a3e-52d4-4b0e-9a57-3c1b2f9e7d10")
+
+// MinPseudonymKeyLength - minimal length of the pseudonym key in bytes
+const MinPseudonymKeyLength = 16
+
 // StudentsRegister - container for all of the registered parsers
 type StudentsRegister struct {
 	Users map[string]uuid.UUID `json:"Users"`
+	// Key - when set, the pseudonyms are derived from the UCO using the HMAC instead of random UUIDs
+	Key []byte `json:"-"`
 }
 
 // NewStudentsRegister - create a new instance
@@ -19,13 +29,54 @@ func NewStudentsRegister() StudentsRegister {
 	return StudentsRegister{Users: make(map[string]uuid.UUID)}
 }
 
+// NewKeyedStudentsRegister - create a new instance deriving the pseudonyms from the key
+func NewKeyedStudentsRegister(key []byte) (StudentsRegister, error) {
+	if len(key) < MinPseudonymKeyLength {
+		return StudentsRegister{}, fmt.Errorf("pseudonym key is too short: %d bytes, at least %d required", len(key), MinPseudonymKeyLength)
+	}
+	return StudentsRegister{Users: make(map[string]uuid.UUID), Key: key}, nil
+}
+
+// DeriveStudentID - derives the pseudonym of the student from the UCO and the key (HMAC-SHA256)
+//
+// The same key and UCO always produce the same ID, so the datasets anonymised
+// by the same key can be joined without sharing the students register.
+func DeriveStudentID(key []byte, uco string) uuid.UUID {
+	return uuid.NewHash(hmac.New(sha256.New, key), pseudonymNamespace, []byte(uco), 8)
+}
+
+// Rekey - derives the pseudonyms of all the registered students from the new key
+//
+// It returns the mapping of the previous IDs to the new ones.
+func (register *StudentsRegister) Rekey(key []byte) map[uuid.UUID]uuid.UUID {
+	mapping := make(map[uuid.UUID]uuid.UUID)
+	for uco, oldID := range register.Users {
+		newID := DeriveStudentID(key, uco)
+		mapping[oldID] = newID
+		if register.Key != nil {
+			mapping[DeriveStudentID(register.Key, uco)] = newID
+		}
+		register.Users[uco] = newID
+	}
+	register.Key = key
+	return mapping
+}
+
 // Register a new parser
 func (register *StudentsRegister) Register(uco string, uuid uuid.UUID) {
 	register.Users[uco] = uuid
 }
 
 // GetOrRegister new UUID for the provided uco
+//
+// The keyed register always derives the ID, so the stale IDs loaded from the file are replaced.
 func (register *StudentsRegister) GetOrRegister(uco string) uuid.UUID {
+	if register.Key != nil {
+		value := DeriveStudentID(register.Key, uco)
+		register.Users[uco] = value
+		return value
+	}
+
 	value, ok := register.Users[uco]
 	if !ok {
 		value = uuid.New()
@@ -44,27 +95,44 @@ func (register *StudentsRegister) Get(uco string) (uuid.UUID, error) {
 }
 
 // Export students register to a provided file
-func (register *StudentsRegister) Export(file string) {
+//
+// The file contains the de-anonymisation mapping, so it is readable only by the owner.
+func (register *StudentsRegister) Export(file string) error {
 	content, err := json.MarshalIndent(register.Users, "", "  ")
 	if err != nil {
 		log.WithError(err).Error("Unable to marshall file")
+		return err
 	}
 
-	if err = ioutil.WriteFile(file, content, 0644); err != nil {
+	if err = WriteFileAtomic(file, content, 0600); err != nil {
 		log.WithError(err).WithField("filepath", file).Error("unable to save marshall file")
+		return err
 	}
+	return nil
 }
 
 // Import the file content to the register
+//
+// The imported students are merged with the already registered ones.
+// Missing file is reported by the error satisfying os.IsNotExist.
 func (register *StudentsRegister) Import(file string) error {
-
 	content, err := ioutil.ReadFile(file)
 	if err != nil {
-		log.WithField("filepath", file).WithError(err).Error("unable to read a file")
+		log.WithField("filepath", file).WithError(err).Debug("unable to read a file")
+		return err
 	}
 
-	if err = json.Unmarshal(content, register.Users); err != nil {
+	users := make(map[string]uuid.UUID)
+	if err = json.Unmarshal(content, &users); err != nil {
 		log.WithField("filepath", file).WithError(err).Error("unable to unmarshal a file")
+		return err
+	}
+
+	if register.Users == nil {
+		register.Users = make(map[string]uuid.UUID)
+	}
+	for uco, uid := range users {
+		register.Users[uco] = uid
 	}
 
 	return nil
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestStudentsRegister_ExportImport(t *testing.T) {
+	// GIVEN
+	dir, err := ioutil.TempDir("", "isstat-register")
+	if err != nil {
+		t.Fatalf("FAIL: Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	file := path.Join(dir, "register.json")
+
+	register := NewStudentsRegister()
+	uid := register.GetOrRegister("123456")
+
+	// WHEN
+	if err := register.Export(file); err != nil {
+		t.Fatalf("FAIL: Unable to export: %v", err)
+	}
+	imported := NewStudentsRegister()
+	err = imported.Import(file)
+
+	// THEN
+	if err != nil {
+		t.Fatalf("FAIL: Unable to import: %v", err)
+	}
+	if imported.GetOrRegister("123456") != uid {
+		t.Error("FAIL: Imported student should keep the pseudonym")
+	}
+}
+
+func TestStudentsRegister_ImportMissingFile(t *testing.T) {
+	// GIVEN
+	register := NewStudentsRegister()
+
+	// WHEN
+	err := register.Import("/nonexistent/register.json")
+
+	// THEN
+	if !os.IsNotExist(err) {
+		t.Errorf("FAIL: Expected not exist error, got: %v", err)
+	}
+}
+
+func TestKeyedStudentsRegister_Deterministic(t *testing.T) {
+	// GIVEN
+	key := []byte("0123456789abcdef-secret")
+	first, _ := NewKeyedStudentsRegister(key)
+	second, _ := NewKeyedStudentsRegister(key)
+	other, _ := NewKeyedStudentsRegister([]byte("another-secret-key-0123"))
+
+	// WHEN
+	firstID := first.GetOrRegister("123456")
+	secondID := second.GetOrRegister("123456")
+	otherID := other.GetOrRegister("123456")
+
+	// THEN
+	if firstID != secondID {
+		t.Errorf("FAIL: Same key should produce the same ID: %v != %v", firstID, secondID)
+	}
+	if firstID == otherID {
+		t.Error("FAIL: Different keys should produce different IDs")
+	}
+	if firstID == first.GetOrRegister("654321") {
+		t.Error("FAIL: Different students should have different IDs")
+	}
+}
+
+func TestKeyedStudentsRegister_ShortKey(t *testing.T) {
+	// WHEN
+	_, err := NewKeyedStudentsRegister([]byte("short"))
+
+	// THEN
+	if err == nil {
+		t.Error("FAIL: Short key should be rejected")
+	}
+}
+
+func TestStudentsRegister_Rekey(t *testing.T) {
+	// GIVEN
+	register := NewStudentsRegister()
+	oldID := register.GetOrRegister("123456")
+	key := []byte("0123456789abcdef-secret")
+	students := []StudentInfo{{ID: oldID}, {ID: uuid.New()}}
+
+	// WHEN
+	mapping := register.Rekey(key)
+	missing := RemapStudentIDs(students, mapping)
+
+	// THEN
+	expected := DeriveStudentID(key, "123456")
+	if students[0].ID != expected {
+		t.Errorf("FAIL: Student ID is %v, expected: %v", students[0].ID, expected)
+	}
+	if register.GetOrRegister("123456") != expected {
+		t.Error("FAIL: Register should use the new key")
+	}
+	if missing != 1 {
+		t.Errorf("FAIL: Missing is %d, expected: %d", missing, 1)
+	}
+}
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"math"
+	"sort"
+)
+
+// ValueSummary - descriptive statistics of a set of values
+type ValueSummary struct {
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	StdDev float64 `json:"stddev"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+}
+
+// NotepadSummary - score summary of one parsed notepad snapshot
+type NotepadSummary struct {
+	Name        string       `json:"name"`
+	TimeStamp   string       `json:"timestamp"`
+	Students    int          `json:"students"`
+	Submitted   int          `json:"submitted"`
+	Submissions int          `json:"submissions"`
+	WithFinal   int          `json:"with_final"`
+	FinalShare  float64      `json:"final_share"`
+	Points      ValueSummary `json:"points"`
+	Bonus       ValueSummary `json:"bonus"`
+}
+
+// NewValueSummary - computes the descriptive statistics for the values
+func NewValueSummary(values []float64) ValueSummary {
+	summary := ValueSummary{Count: len(values)}
+	if len(values) == 0 {
+		return summary
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, value := range sorted {
+		sum += value
+	}
+
+	summary.Min = sorted[0]
+	summary.Max = sorted[len(sorted)-1]
+	summary.Mean = sum / float64(len(sorted))
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		summary.Median = (sorted[middle-1] + sorted[middle]) / 2
+	} else {
+		summary.Median = sorted[middle]
+	}
+
+	var variance float64
+	for _, value := range sorted {
+		variance += (value - summary.Mean) * (value - summary.Mean)
+	}
+	summary.StdDev = math.Sqrt(variance / float64(len(sorted)))
+
+	return summary
+}
+
+// FinalSubmission - gets the submission that counts for the student
+//
+// The last submission marked as final wins, if there is none, the last submission is used.
+// The second return value is false when the student has no submissions.
+func (info *StudentInfo) FinalSubmission() (Submission, bool) {
+	if len(info.Submissions) == 0 {
+		return Submission{}, false
+	}
+
+	var result *Submission
+	for i := range info.Submissions {
+		submission := &info.Submissions[i]
+		if submission.Final && (result == nil || submission.Index >= result.Index) {
+			result = submission
+		}
+	}
+
+	if result != nil {
+		return *result, true
+	}
+
+	result = &info.Submissions[0]
+	for i := range info.Submissions {
+		if info.Submissions[i].Index >= result.Index {
+			result = &info.Submissions[i]
+		}
+	}
+	return *result, true
+}
+
+// HasFinal - whether the student has a submission marked as final
+func (info *StudentInfo) HasFinal() bool {
+	for _, submission := range info.Submissions {
+		if submission.Final {
+			return true
+		}
+	}
+	return false
+}
+
+// ComputeNotepadSummary - computes the score summary for the notepad students
+func ComputeNotepadSummary(name, timestamp string, students []StudentInfo) NotepadSummary {
+	summary := NotepadSummary{Name: name, TimeStamp: timestamp, Students: len(students)}
+
+	var points, bonus []float64
+
+	for i := range students {
+		student := &students[i]
+		summary.Submissions += len(student.Submissions)
+
+		if student.HasFinal() {
+			summary.WithFinal++
+		}
+
+		submission, ok := student.FinalSubmission()
+		if !ok {
+			continue
+		}
+
+		summary.Submitted++
+		points = append(points, submission.Points)
+		bonus = append(bonus, submission.Bonus)
+	}
+
+	if summary.Students > 0 {
+		summary.FinalShare = float64(summary.WithFinal) / float64(summary.Students)
+	}
+
+	summary.Points = NewValueSummary(points)
+	summary.Bonus = NewValueSummary(bonus)
+
+	return summary
+}
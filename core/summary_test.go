@@ -0,0 +1,92 @@
+package core
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewValueSummary_Odd(t *testing.T) {
+	// GIVEN
+	input := []float64{4, 1, 3, 2, 5}
+
+	// WHEN
+	summary := NewValueSummary(input)
+
+	// THEN
+	assertFloat(t, "mean", 3, summary.Mean)
+	assertFloat(t, "median", 3, summary.Median)
+	assertFloat(t, "stddev", math.Sqrt(2), summary.StdDev)
+	assertFloat(t, "min", 1, summary.Min)
+	assertFloat(t, "max", 5, summary.Max)
+
+	if input[0] != 4 {
+		t.Error("FAIL: Input values should not be modified")
+	}
+}
+
+func TestNewValueSummary_EvenMedian(t *testing.T) {
+	// GIVEN
+	input := []float64{1, 2, 3, 10}
+
+	// WHEN
+	summary := NewValueSummary(input)
+
+	// THEN
+	assertFloat(t, "median", 2.5, summary.Median)
+}
+
+func TestNewValueSummary_Empty(t *testing.T) {
+	// WHEN
+	summary := NewValueSummary(nil)
+
+	// THEN
+	if summary.Count != 0 || summary.Mean != 0 || summary.Max != 0 {
+		t.Errorf("FAIL: Empty summary expected, got: %v", summary)
+	}
+}
+
+func TestComputeNotepadSummary(t *testing.T) {
+	// GIVEN
+	now := time.Now()
+	students := []StudentInfo{
+		{ID: uuid.New(), Submissions: []Submission{
+			{DateTime: now, Index: 1, Points: 1},
+			{DateTime: now, Index: 2, Points: 4, Final: true, Bonus: 1},
+			{DateTime: now, Index: 3, Points: 2},
+		}},
+		{ID: uuid.New(), Submissions: []Submission{
+			{DateTime: now, Index: 1, Points: 2},
+		}},
+		{ID: uuid.New(), Submissions: []Submission{}},
+	}
+
+	// WHEN
+	summary := ComputeNotepadSummary("hw01", "2020-03-01T10-00-00", students)
+
+	// THEN
+	if summary.Students != 3 {
+		t.Errorf("FAIL: Students is %d, expected: %d", summary.Students, 3)
+	}
+	if summary.Submitted != 2 {
+		t.Errorf("FAIL: Submitted is %d, expected: %d", summary.Submitted, 2)
+	}
+	if summary.Submissions != 4 {
+		t.Errorf("FAIL: Submissions is %d, expected: %d", summary.Submissions, 4)
+	}
+	if summary.WithFinal != 1 {
+		t.Errorf("FAIL: WithFinal is %d, expected: %d", summary.WithFinal, 1)
+	}
+	assertFloat(t, "final share", 1.0/3.0, summary.FinalShare)
+	assertFloat(t, "points mean", 3, summary.Points.Mean)
+	assertFloat(t, "bonus max", 1, summary.Bonus.Max)
+}
+
+func assertFloat(t *testing.T, name string, expected, provided float64) {
+	t.Helper()
+	if math.Abs(expected-provided) > 1e-9 {
+		t.Errorf("FAIL: %s is %v, expected: %v", name, provided, expected)
+	}
+}
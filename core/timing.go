@@ -0,0 +1,104 @@
+package core
+
+import (
+	"time"
+)
+
+// LeadTimeBucket - number of the students whose submission was made within the window before the deadline
+//
+// The window is [From, To) hours before the deadline, the late submissions have the negative hours.
+type LeadTimeBucket struct {
+	Label string  `json:"label"`
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Count int     `json:"count"`
+}
+
+// leadTimeWindows - windows of the lead time distribution, from the latest
+var leadTimeWindows = []LeadTimeBucket{
+	{Label: "late", From: -1e9, To: 0},
+	{Label: "< 1h", From: 0, To: 1},
+	{Label: "1-6h", From: 1, To: 6},
+	{Label: "6-24h", From: 6, To: 24},
+	{Label: "1-3d", From: 24, To: 72},
+	{Label: "3-7d", From: 72, To: 168},
+	{Label: "> 7d", From: 168, To: 1e9},
+}
+
+// TimingAnalysis - submission times of the notepad relative to its deadline
+type TimingAnalysis struct {
+	Notepad   string    `json:"notepad"`
+	TimeStamp string    `json:"timestamp"`
+	Deadline  time.Time `json:"deadline"`
+	// FirstHours - hours before the deadline of the students' first submissions (negative when late)
+	FirstHours ValueSummary     `json:"first_hours"`
+	First      []LeadTimeBucket `json:"first"`
+	// FinalHours - hours before the deadline of the students' final submissions (negative when late)
+	FinalHours ValueSummary     `json:"final_hours"`
+	Final      []LeadTimeBucket `json:"final"`
+	Lateness   Lateness         `json:"lateness"`
+	// Heatmap - submissions per day of the week (Monday first) and hour of the day
+	Heatmap [7][24]int `json:"heatmap"`
+}
+
+// NewTimingAnalysis - analyses the submission times in the location against the deadline
+//
+// The submissions without the time are skipped.
+func NewTimingAnalysis(notepad, timestamp string, students []StudentInfo, deadline time.Time, location *time.Location) TimingAnalysis {
+	analysis := TimingAnalysis{
+		Notepad:   notepad,
+		TimeStamp: timestamp,
+		Deadline:  deadline,
+		Lateness:  NewLateness(students, deadline),
+	}
+
+	var first, final []float64
+	for i := range students {
+		student := &students[i]
+
+		var earliest time.Time
+		for _, submission := range student.Submissions {
+			if submission.DateTime.IsZero() {
+				continue
+			}
+			if earliest.IsZero() || submission.DateTime.Before(earliest) {
+				earliest = submission.DateTime
+			}
+
+			local := submission.DateTime.In(location)
+			analysis.Heatmap[(int(local.Weekday())+6)%7][local.Hour()]++
+		}
+
+		if !earliest.IsZero() {
+			first = append(first, hoursBefore(earliest, deadline))
+		}
+		if submission, ok := student.FinalSubmission(); ok && !submission.DateTime.IsZero() {
+			final = append(final, hoursBefore(submission.DateTime, deadline))
+		}
+	}
+
+	analysis.FirstHours = NewValueSummary(first)
+	analysis.First = newLeadTimeBuckets(first)
+	analysis.FinalHours = NewValueSummary(final)
+	analysis.Final = newLeadTimeBuckets(final)
+	return analysis
+}
+
+func hoursBefore(t, deadline time.Time) float64 {
+	return deadline.Sub(t).Hours()
+}
+
+func newLeadTimeBuckets(hours []float64) []LeadTimeBucket {
+	buckets := make([]LeadTimeBucket, len(leadTimeWindows))
+	copy(buckets, leadTimeWindows)
+
+	for _, value := range hours {
+		for i := range buckets {
+			if value >= buckets[i].From && value < buckets[i].To {
+				buckets[i].Count++
+				break
+			}
+		}
+	}
+	return buckets
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewTimingAnalysis(t *testing.T) {
+	// GIVEN
+	// Sunday
+	deadline := time.Date(2020, 3, 8, 23, 59, 0, 0, time.UTC)
+	students := []StudentInfo{
+		{Submissions: []Submission{
+			{Index: 0, DateTime: deadline.Add(-48 * time.Hour)},
+			{Index: 1, DateTime: deadline.Add(-2 * time.Hour), Final: true},
+		}},
+		{Submissions: []Submission{
+			{Index: 0, DateTime: deadline.Add(-30 * time.Minute)},
+			{Index: 1, DateTime: deadline.Add(time.Hour), Final: true},
+		}},
+		{Submissions: []Submission{{Index: 0}}},
+	}
+
+	// WHEN
+	analysis := NewTimingAnalysis("hw01", "2020-03-09T10-00-00", students, deadline, time.UTC)
+
+	// THEN
+	assertFloat(t, "first mean", 24.25, analysis.FirstHours.Mean)
+	assertFloat(t, "final min", -1, analysis.FinalHours.Min)
+	if analysis.FirstHours.Count != 2 || analysis.FinalHours.Count != 2 {
+		t.Errorf("FAIL: Students without the submission times should be skipped: %+v", analysis)
+	}
+
+	counts := func(buckets []LeadTimeBucket) map[string]int {
+		result := make(map[string]int)
+		for _, bucket := range buckets {
+			result[bucket.Label] = bucket.Count
+		}
+		return result
+	}
+	if first := counts(analysis.First); first["1-3d"] != 1 || first["< 1h"] != 1 {
+		t.Errorf("FAIL: Unexpected first submission buckets %+v", analysis.First)
+	}
+	if final := counts(analysis.Final); final["late"] != 1 || final["1-6h"] != 1 {
+		t.Errorf("FAIL: Unexpected final submission buckets %+v", analysis.Final)
+	}
+
+	if analysis.Lateness.Late != 1 || analysis.Lateness.Submissions != 4 {
+		t.Errorf("FAIL: Unexpected lateness %+v", analysis.Lateness)
+	}
+
+	// Friday 23:59 and Sunday 21:59, 23:29 and Monday 00:59
+	if analysis.Heatmap[4][23] != 1 || analysis.Heatmap[6][21] != 1 || analysis.Heatmap[6][23] != 1 || analysis.Heatmap[0][0] != 1 {
+		t.Errorf("FAIL: Unexpected heatmap %v", analysis.Heatmap)
+	}
+}
+
+func TestNewLeadTimeBuckets_DeadlineIsOnTime(t *testing.T) {
+	// WHEN
+	buckets := newLeadTimeBuckets([]float64{0, -0.1})
+
+	// THEN
+	if buckets[0].Count != 1 || buckets[1].Count != 1 {
+		t.Errorf("FAIL: Submission at the deadline should be on time: %+v", buckets)
+	}
+}
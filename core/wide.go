@@ -0,0 +1,152 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Pivots of the wide table
+const (
+	// PivotAttempt - one row per notepad and student, one column per submission index
+	PivotAttempt = "attempt"
+	// PivotNotepad - one row per student, one column per notepad
+	PivotNotepad = "notepad"
+)
+
+// Points of the student in the notepad pivot
+const (
+	// WidePointsFinal - points of the final submission
+	WidePointsFinal = "final"
+	// WidePointsBest - the best points of all the submissions
+	WidePointsBest = "best"
+)
+
+// WideRow - key cells (e.g. the student ID) followed by the points, NaN points are missing
+type WideRow struct {
+	Keys   []string
+	Points []float64
+}
+
+// WideTable - pivoted points, one row per student
+type WideTable struct {
+	KeyColumns   []string
+	PointColumns []string
+	Rows         []WideRow
+}
+
+// NewAttemptsTable - points of the students' attempts keyed by the submission index, per notepad
+//
+// There is one column per submission index of any student (attempt_INDEX), so the same column holds
+// the same attempt even when the student's earlier submissions are missing.
+// The rows are ordered by the notepad and the student ID, the attempts the student did not make are missing.
+func NewAttemptsTable(notepads map[string][]StudentInfo) WideTable {
+	table := WideTable{KeyColumns: []string{"notepad", "student_id"}}
+
+	seen := make(map[int]bool)
+	var indexes []int
+	for _, students := range notepads {
+		for i := range students {
+			for _, submission := range students[i].Submissions {
+				if !seen[submission.Index] {
+					seen[submission.Index] = true
+					indexes = append(indexes, submission.Index)
+				}
+			}
+		}
+	}
+	sort.Ints(indexes)
+
+	columns := make(map[int]int, len(indexes))
+	for column, index := range indexes {
+		columns[index] = column
+		table.PointColumns = append(table.PointColumns, "attempt_"+strconv.Itoa(index))
+	}
+
+	for _, notepad := range sortedNotepads(notepads) {
+		for _, student := range sortedStudents(notepads[notepad]) {
+			row := WideRow{Keys: []string{notepad, student.ID.String()}, Points: make([]float64, len(indexes))}
+			for i := range row.Points {
+				row.Points[i] = math.NaN()
+			}
+			for _, submission := range student.Submissions {
+				row.Points[columns[submission.Index]] = submission.Points
+			}
+			table.Rows = append(table.Rows, row)
+		}
+	}
+	return table
+}
+
+// NewNotepadsTable - final or best points of the students (joined by the ID) in the notepads
+//
+// The rows are ordered by the student ID, the notepads without the student's submission are missing.
+func NewNotepadsTable(notepads map[string][]StudentInfo, points string) (WideTable, error) {
+	if points != WidePointsFinal && points != WidePointsBest {
+		return WideTable{}, fmt.Errorf("unknown points '%s', expected final or best", points)
+	}
+
+	names := sortedNotepads(notepads)
+	table := WideTable{KeyColumns: []string{"student_id"}, PointColumns: names}
+
+	rows := make(map[string]*WideRow)
+	var ids []string
+	for column, notepad := range names {
+		for i := range notepads[notepad] {
+			student := &notepads[notepad][i]
+			id := student.ID.String()
+			row, ok := rows[id]
+			if !ok {
+				row = &WideRow{Keys: []string{id}, Points: make([]float64, len(names))}
+				for j := range row.Points {
+					row.Points[j] = math.NaN()
+				}
+				rows[id] = row
+				ids = append(ids, id)
+			}
+			row.Points[column] = studentPoints(student, points)
+		}
+	}
+
+	sort.Strings(ids)
+	for _, id := range ids {
+		table.Rows = append(table.Rows, *rows[id])
+	}
+	return table, nil
+}
+
+// studentPoints - final or best points of the student, NaN without submissions
+func studentPoints(student *StudentInfo, points string) float64 {
+	if len(student.Submissions) == 0 {
+		return math.NaN()
+	}
+	if points == WidePointsFinal {
+		submission, _ := student.FinalSubmission()
+		return submission.Points
+	}
+
+	best := math.Inf(-1)
+	for _, submission := range student.Submissions {
+		best = math.Max(best, submission.Points)
+	}
+	return best
+}
+
+func sortedNotepads(notepads map[string][]StudentInfo) []string {
+	names := make([]string, 0, len(notepads))
+	for name := range notepads {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedStudents(students []StudentInfo) []StudentInfo {
+	sorted := make([]StudentInfo, len(students))
+	copy(sorted, students)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ID.String() < sorted[j].ID.String()
+	})
+	return sorted
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func wideNotepads() (uuid.UUID, uuid.UUID, map[string][]StudentInfo) {
+	first := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	second := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	return first, second, map[string][]StudentInfo{
+		"hw02": {{ID: first, Submissions: []Submission{{Index: 2, Points: 4}}}},
+		"hw01": {
+			{ID: second, Submissions: []Submission{{Index: 1, Points: 6, Final: true}, {Index: 0, Points: 8}, {Index: 2, Points: 2.5}}},
+			{ID: first, Submissions: []Submission{{Index: 0, Points: 10}}},
+		},
+	}
+}
+
+func TestNewAttemptsTable(t *testing.T) {
+	// GIVEN
+	first, second, notepads := wideNotepads()
+
+	// WHEN
+	table := NewAttemptsTable(notepads)
+
+	// THEN
+	if len(table.PointColumns) != 3 || table.PointColumns[0] != "attempt_0" || table.PointColumns[2] != "attempt_2" || len(table.Rows) != 3 {
+		t.Fatalf("FAIL: Unexpected table %+v", table)
+	}
+	if table.Rows[0].Keys[0] != "hw01" || table.Rows[0].Keys[1] != first.String() || table.Rows[2].Keys[0] != "hw02" {
+		t.Errorf("FAIL: Expected the rows ordered by the notepad and the student, got %+v", table.Rows)
+	}
+	if !math.IsNaN(table.Rows[0].Points[1]) {
+		t.Errorf("FAIL: Expected the missing 2nd attempt, got %v", table.Rows[0].Points)
+	}
+	if table.Rows[1].Keys[1] != second.String() || table.Rows[1].Points[0] != 8 || table.Rows[1].Points[2] != 2.5 {
+		t.Errorf("FAIL: Expected the attempts ordered by the index, got %v", table.Rows[1].Points)
+	}
+	if points := table.Rows[2].Points; !math.IsNaN(points[0]) || !math.IsNaN(points[1]) || points[2] != 4 {
+		t.Errorf("FAIL: Expected the attempt in the column of its index, got %v", points)
+	}
+}
+
+func TestNewNotepadsTable(t *testing.T) {
+	// GIVEN
+	_, _, notepads := wideNotepads()
+	format := CSVFormat{Decimal: ",", Delimiter: ";"}
+
+	// WHEN
+	final, err := NewNotepadsTable(notepads, WidePointsFinal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	best, _ := NewNotepadsTable(notepads, WidePointsBest)
+	content, err := format.MarshalWide(&final)
+
+	// THEN
+	expected := "student_id;hw01;hw02\n" +
+		"00000000-0000-0000-0000-000000000001;10;4\n" +
+		"00000000-0000-0000-0000-000000000002;6;\n"
+	if err != nil || string(content) != expected {
+		t.Errorf("FAIL: Expected %q, got %q (%v)", expected, content, err)
+	}
+	if best.Rows[1].Points[0] != 8 {
+		t.Errorf("FAIL: Expected the best points 8, got %v", best.Rows[1].Points)
+	}
+	if _, err := NewNotepadsTable(notepads, "mean"); err == nil {
+		t.Errorf("FAIL: Expected the unknown points to fail")
+	}
+}
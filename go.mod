@@ -6,8 +6,10 @@ require (
 	github.com/gocarina/gocsv v0.0.0-20200302151839-87c60d755c58
 	github.com/google/uuid v1.1.1
 	github.com/mitchellh/go-homedir v1.1.0
+	github.com/robfig/cron/v3 v3.0.1
 	github.com/sirupsen/logrus v1.4.2
 	github.com/spf13/cobra v0.0.6
 	github.com/spf13/viper v1.6.2
+	go.etcd.io/bbolt v1.3.4
 	gopkg.in/yaml.v2 v2.2.4
 )
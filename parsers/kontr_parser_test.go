@@ -61,7 +61,7 @@ func TestParseNumber_OneFinal(t *testing.T) {
 	//GIVEN
 	input := "*1"
 
-	var points int = 0
+	var points float64 = 0
 	var isFinal bool = false
 	var err error
 
@@ -78,7 +78,7 @@ func TestParseNumber_OneFinal(t *testing.T) {
 	}
 
 	if points != 1 {
-		t.Errorf("FAIL: points should 1, parsed: %d", points)
+		t.Errorf("FAIL: points should 1, parsed: %v", points)
 	}
 }
 
@@ -86,7 +86,7 @@ func TestParseNumber_OneNormal(t *testing.T) {
 	//GIVEN
 	input := "1"
 
-	var points int = 0
+	var points float64 = 0
 	var isFinal bool = false
 	var err error
 
@@ -103,7 +103,7 @@ func TestParseNumber_OneNormal(t *testing.T) {
 	}
 
 	if points != 1 {
-		t.Errorf("FAIL: points should be 1, parsed: %d", points)
+		t.Errorf("FAIL: points should be 1, parsed: %v", points)
 	}
 }
 
@@ -111,7 +111,7 @@ func TestParseNumber_NegativeNormal(t *testing.T) {
 	//GIVEN
 	input := "-1"
 
-	var points int = 0
+	var points float64 = 0
 	var isFinal bool = false
 	var err error
 
@@ -128,7 +128,7 @@ func TestParseNumber_NegativeNormal(t *testing.T) {
 	}
 
 	if points != -1 {
-		t.Errorf("FAIL: points should be -1, parsed: %d", points)
+		t.Errorf("FAIL: points should be -1, parsed: %v", points)
 	}
 }
 
@@ -136,7 +136,7 @@ func TestParseNumber_NegativeFinal(t *testing.T) {
 	//GIVEN
 	input := "*-10"
 
-	var points int = 0
+	var points float64 = 0
 	var isFinal bool = false
 	var err error
 
@@ -153,7 +153,7 @@ func TestParseNumber_NegativeFinal(t *testing.T) {
 	}
 
 	if points != -10 {
-		t.Errorf("FAIL: points should be -10, parsed: %d", points)
+		t.Errorf("FAIL: points should be -10, parsed: %v", points)
 	}
 }
 
@@ -163,7 +163,7 @@ func assertSubmission(t *testing.T, expected *core.Submission, provided *core.Su
 	}
 
 	if provided.Points != expected.Points {
-		t.Errorf("FAIL: Submission Points is %d, expected: %d", provided.Points, expected.Points)
+		t.Errorf("FAIL: Submission Points is %v, expected: %v", provided.Points, expected.Points)
 	}
 
 	if provided.Final != expected.Final {
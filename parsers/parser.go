@@ -23,8 +23,10 @@ type Parser interface {
 
 // BasicParser implementation
 type BasicParser struct {
-	StudentsRegister core.StudentsRegister
+	StudentsRegister *core.StudentsRegister
 	NotepadContentParser NotepadContentParser
+	// KontrAccounts - accounts (UCOs) of the Kontr, the entries edited by other accounts are flagged
+	KontrAccounts []string
 }
 
 // Parse the provided student's content using the Basic parser
@@ -36,6 +38,7 @@ func (parser *BasicParser) Parse(content *core.NotepadContent) ([]core.StudentIn
 		var err error
 
 		students[i] = core.NewStudentSubmissions(uid)
+		students[i].EditedByOther = core.IsEditedByOther(parser.KontrAccounts, student.ChangedBy)
 
 		log.WithField("index", i).WithField("student_uco", student.Uco).WithField("content", student.Content).Debug("parsing content")
 		students[i].Submissions, err = parser.NotepadContentParser.Parse(student.Content)
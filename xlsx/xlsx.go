@@ -0,0 +1,380 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxSheetName - max length of the sheet name in Excel
+const MaxSheetName = 31
+
+// Column widths in characters
+const (
+	minColumnWidth = 8
+	maxColumnWidth = 60
+)
+
+// Cell styles, the indexes of the cellXfs in the styles part
+const (
+	styleDefault = iota
+	styleHeader
+	styleDateTime
+	stylePercent
+)
+
+// excelEpoch - day zero of the Excel 1900 date system (the 1900 leap year bug included)
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// Cell - one typed cell, the zero value is the empty cell
+type Cell struct {
+	kind    byte
+	text    string
+	number  float64
+	boolean bool
+	style   int
+}
+
+// Cell kinds
+const (
+	cellEmpty byte = iota
+	cellString
+	cellNumber
+	cellBool
+)
+
+// String - text cell
+func String(text string) Cell {
+	return Cell{kind: cellString, text: text}
+}
+
+// Number - numeric cell, NaN and infinities are empty
+func Number(value float64) Cell {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return Cell{}
+	}
+	return Cell{kind: cellNumber, number: value}
+}
+
+// Percent - numeric cell of the share (0.5 = 50 %) formatted as the percentage
+func Percent(share float64) Cell {
+	cell := Number(share)
+	if cell.kind == cellNumber {
+		cell.style = stylePercent
+	}
+	return cell
+}
+
+// DateTime - date cell of the wall clock time in the time's location, zero time is empty
+func DateTime(t time.Time) Cell {
+	if t.IsZero() {
+		return Cell{}
+	}
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return Cell{kind: cellNumber, number: wall.Sub(excelEpoch).Hours() / 24, style: styleDateTime}
+}
+
+// Bool - boolean cell
+func Bool(value bool) Cell {
+	return Cell{kind: cellBool, boolean: value}
+}
+
+// Sheet - worksheet with the header row (frozen, with the autofilter) followed by the rows
+type Sheet struct {
+	Name   string
+	Header []string
+	Rows   [][]Cell
+}
+
+// Workbook - the sheets of the workbook in the order
+type Workbook struct {
+	Sheets []Sheet
+}
+
+// Write - writes the workbook as the Office Open XML spreadsheet (xlsx)
+//
+// The sheet names are sanitized and made unique (see SheetName).
+func (workbook *Workbook) Write(w io.Writer) error {
+	if len(workbook.Sheets) == 0 {
+		return fmt.Errorf("workbook has no sheets")
+	}
+
+	names := make([]string, len(workbook.Sheets))
+	used := make(map[string]bool)
+	for i := range workbook.Sheets {
+		names[i] = uniqueSheetName(SheetName(workbook.Sheets[i].Name), used)
+	}
+
+	archive := zip.NewWriter(w)
+	parts := []struct {
+		name    string
+		content []byte
+	}{
+		{"[Content_Types].xml", contentTypes(len(names))},
+		{"_rels/.rels", []byte(rootRels)},
+		{"xl/workbook.xml", workbookXML(workbook.Sheets, names)},
+		{"xl/_rels/workbook.xml.rels", workbookRels(len(names))},
+		{"xl/styles.xml", []byte(stylesXML)},
+	}
+	for i := range workbook.Sheets {
+		parts = append(parts, struct {
+			name    string
+			content []byte
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), workbook.Sheets[i].xml()})
+	}
+
+	for _, part := range parts {
+		writer, err := archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := writer.Write(part.content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// SheetName - the name without the characters Excel forbids, at most MaxSheetName characters
+func SheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, strings.Trim(name, "'"))
+	if name == "" {
+		name = "Sheet"
+	}
+	if utf8.RuneCountInString(name) > MaxSheetName {
+		name = string([]rune(name)[:MaxSheetName])
+	}
+	return name
+}
+
+// uniqueSheetName - the name with the numeric suffix when already used (case-insensitive as in Excel)
+func uniqueSheetName(name string, used map[string]bool) string {
+	unique := name
+	for n := 2; used[strings.ToLower(unique)]; n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		runes := []rune(name)
+		if len(runes)+len(suffix) > MaxSheetName {
+			runes = runes[:MaxSheetName-len(suffix)]
+		}
+		unique = string(runes) + suffix
+	}
+	used[strings.ToLower(unique)] = true
+	return unique
+}
+
+// ColumnName - letters of the zero-based column index (0 = A, 26 = AA)
+func ColumnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
+
+// dimensions - number of the columns and the last row (the header included)
+func (sheet *Sheet) dimensions() (int, int) {
+	columns := len(sheet.Header)
+	for _, row := range sheet.Rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	return columns, len(sheet.Rows) + 1
+}
+
+// reference - range of all the sheet's cells, e.g. A1:F10 (or $A$1:$F$10 when absolute)
+func (sheet *Sheet) reference(absolute bool) string {
+	columns, rows := sheet.dimensions()
+	if columns == 0 {
+		columns = 1
+	}
+	if absolute {
+		return fmt.Sprintf("$A$1:$%s$%d", ColumnName(columns-1), rows)
+	}
+	return fmt.Sprintf("A1:%s%d", ColumnName(columns-1), rows)
+}
+
+func (sheet *Sheet) xml() []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString(xml.Header)
+	buffer.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`)
+	fmt.Fprintf(&buffer, `<dimension ref="%s"/>`, sheet.reference(false))
+	buffer.WriteString(`<sheetViews><sheetView workbookViewId="0">` +
+		`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>` +
+		`<selection pane="bottomLeft" activeCell="A2" sqref="A2"/></sheetView></sheetViews>`)
+
+	if widths := sheet.columnWidths(); len(widths) > 0 {
+		buffer.WriteString(`<cols>`)
+		for i, width := range widths {
+			fmt.Fprintf(&buffer, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, width)
+		}
+		buffer.WriteString(`</cols>`)
+	}
+	buffer.WriteString(`<sheetData>`)
+
+	header := make([]Cell, len(sheet.Header))
+	for i, label := range sheet.Header {
+		header[i] = String(label)
+		header[i].style = styleHeader
+	}
+	writeRow(&buffer, 1, header)
+	for i, row := range sheet.Rows {
+		writeRow(&buffer, i+2, row)
+	}
+
+	fmt.Fprintf(&buffer, `</sheetData><autoFilter ref="%s"/></worksheet>`, sheet.reference(false))
+	return buffer.Bytes()
+}
+
+// columnWidths - widths fitting the header and the cells, the dates and the percentages have the fixed width
+func (sheet *Sheet) columnWidths() []int {
+	columns, _ := sheet.dimensions()
+	widths := make([]int, columns)
+	fit := func(i, length int) {
+		if length > widths[i] {
+			widths[i] = length
+		}
+	}
+
+	for i, label := range sheet.Header {
+		// the autofilter button takes the space of about 3 characters
+		fit(i, utf8.RuneCountInString(label)+3)
+	}
+	for _, row := range sheet.Rows {
+		for i, cell := range row {
+			switch {
+			case cell.kind == cellString:
+				fit(i, utf8.RuneCountInString(cell.text))
+			case cell.style == styleDateTime:
+				fit(i, 16)
+			case cell.style == stylePercent:
+				fit(i, 7)
+			case cell.kind == cellNumber:
+				fit(i, len(strconv.FormatFloat(cell.number, 'f', -1, 64)))
+			}
+		}
+	}
+
+	for i := range widths {
+		widths[i] += 2
+		if widths[i] < minColumnWidth {
+			widths[i] = minColumnWidth
+		}
+		if widths[i] > maxColumnWidth {
+			widths[i] = maxColumnWidth
+		}
+	}
+	return widths
+}
+
+func writeRow(buffer *bytes.Buffer, number int, cells []Cell) {
+	fmt.Fprintf(buffer, `<row r="%d">`, number)
+	for i, cell := range cells {
+		reference := fmt.Sprintf("%s%d", ColumnName(i), number)
+		style := ""
+		if cell.style != styleDefault {
+			style = fmt.Sprintf(` s="%d"`, cell.style)
+		}
+
+		switch cell.kind {
+		case cellString:
+			fmt.Fprintf(buffer, `<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">`, reference, style)
+			_ = xml.EscapeText(buffer, []byte(cell.text))
+			buffer.WriteString(`</t></is></c>`)
+		case cellNumber:
+			fmt.Fprintf(buffer, `<c r="%s"%s><v>%s</v></c>`, reference, style, strconv.FormatFloat(cell.number, 'f', -1, 64))
+		case cellBool:
+			value := 0
+			if cell.boolean {
+				value = 1
+			}
+			fmt.Fprintf(buffer, `<c r="%s"%s t="b"><v>%d</v></c>`, reference, style, value)
+		}
+	}
+	buffer.WriteString(`</row>`)
+}
+
+func contentTypes(sheets int) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString(xml.Header)
+	buffer.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&buffer, `<Override PartName="/xl/worksheets/sheet%d.xml" `+
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	buffer.WriteString(`</Types>`)
+	return buffer.Bytes()
+}
+
+const rootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+func workbookXML(sheets []Sheet, names []string) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString(xml.Header)
+	buffer.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, name := range names {
+		buffer.WriteString(`<sheet name="`)
+		_ = xml.EscapeText(&buffer, []byte(name))
+		fmt.Fprintf(&buffer, `" sheetId="%d" r:id="rId%d"/>`, i+1, i+1)
+	}
+	buffer.WriteString(`</sheets><definedNames>`)
+	for i, name := range names {
+		// the autofilter ranges, Excel keeps them as the hidden names
+		reference := "'" + strings.ReplaceAll(name, "'", "''") + "'!" + sheets[i].reference(true)
+		fmt.Fprintf(&buffer, `<definedName name="_xlnm._FilterDatabase" localSheetId="%d" hidden="1">`, i)
+		_ = xml.EscapeText(&buffer, []byte(reference))
+		buffer.WriteString(`</definedName>`)
+	}
+	buffer.WriteString(`</definedNames></workbook>`)
+	return buffer.Bytes()
+}
+
+func workbookRels(sheets int) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString(xml.Header)
+	buffer.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&buffer, `<Relationship Id="rId%d" `+
+			`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+	fmt.Fprintf(&buffer, `<Relationship Id="rId%d" `+
+		`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, sheets+1)
+	buffer.WriteString(`</Relationships>`)
+	return buffer.Bytes()
+}
+
+// stylesXML - the cell styles: default, bold header, date and time, percentage (see the style constants)
+const stylesXML = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy\-mm\-dd\ hh:mm"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="4">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="10" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
@@ -0,0 +1,126 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readParts(t *testing.T, content []byte) map[string]string {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("FAIL: Workbook is not the zip archive: %v", err)
+	}
+
+	parts := make(map[string]string)
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(reader)
+		_ = reader.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[file.Name] = string(data)
+	}
+	return parts
+}
+
+func assertWellFormed(t *testing.T, name, part string) {
+	decoder := xml.NewDecoder(strings.NewReader(part))
+	for {
+		_, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				return
+			}
+			t.Fatalf("FAIL: Part %s is not well-formed: %v\n%s", name, err, part)
+		}
+	}
+}
+
+func TestWorkbook_Write(t *testing.T) {
+	// GIVEN
+	workbook := Workbook{Sheets: []Sheet{
+		{Name: "Summary", Header: []string{"notepad", "finished"}, Rows: [][]Cell{{String("hw01 <&>"), Percent(0.5)}}},
+		{Name: "summary", Header: []string{"when", "points", "final", "missing"}, Rows: [][]Cell{
+			{DateTime(time.Date(2020, 3, 8, 21, 30, 0, 0, time.UTC)), Number(7.5), Bool(true), Number(math.NaN())},
+		}},
+	}}
+	var buffer bytes.Buffer
+
+	// WHEN
+	err := workbook.Write(&buffer)
+
+	// THEN
+	if err != nil {
+		t.Fatalf("FAIL: Unable to write the workbook: %v", err)
+	}
+	parts := readParts(t, buffer.Bytes())
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels",
+		"xl/styles.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		part, ok := parts[name]
+		if !ok {
+			t.Fatalf("FAIL: Missing part %s", name)
+		}
+		assertWellFormed(t, name, part)
+	}
+
+	if !strings.Contains(parts["xl/workbook.xml"], `<sheet name="summary (2)" sheetId="2" r:id="rId2"/>`) {
+		t.Errorf("FAIL: Expected the unique sheet names, got %s", parts["xl/workbook.xml"])
+	}
+
+	first := parts["xl/worksheets/sheet1.xml"]
+	for _, expected := range []string{`state="frozen"`, `<autoFilter ref="A1:B2"/>`, `hw01 &lt;&amp;&gt;`, `<c r="B2" s="3"><v>0.5</v></c>`} {
+		if !strings.Contains(first, expected) {
+			t.Errorf("FAIL: Expected %s in the summary sheet %s", expected, first)
+		}
+	}
+
+	second := parts["xl/worksheets/sheet2.xml"]
+	for _, expected := range []string{`<c r="A2" s="2"><v>43898.89583333333`, `<c r="B2"><v>7.5</v></c>`, `<c r="C2" t="b"><v>1</v></c>`} {
+		if !strings.Contains(second, expected) {
+			t.Errorf("FAIL: Expected %s in the sheet %s", expected, second)
+		}
+	}
+	if strings.Contains(second, `r="D2"`) {
+		t.Errorf("FAIL: Expected the NaN cell to be empty, got %s", second)
+	}
+}
+
+func TestWorkbook_WriteEmpty(t *testing.T) {
+	workbook := Workbook{}
+	if err := workbook.Write(ioutil.Discard); err == nil {
+		t.Errorf("FAIL: Expected the workbook without sheets to fail")
+	}
+}
+
+func TestSheetName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"hw01":     "hw01",
+		"a/b:c?":   "a_b_c_",
+		"'quoted'": "quoted",
+		"":         "Sheet",
+		"a-very-long-notepad-name-over-31-characters": "a-very-long-notepad-name-over-3",
+	} {
+		if provided := SheetName(name); provided != expected {
+			t.Errorf("FAIL: Expected the sheet name %q of %q, got %q", expected, name, provided)
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for index, expected := range map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"} {
+		if provided := ColumnName(index); provided != expected {
+			t.Errorf("FAIL: Expected the column %s of %d, got %s", expected, index, provided)
+		}
+	}
+}